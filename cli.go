@@ -0,0 +1,1429 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"lifting/config"
+	"lifting/e1rm"
+	"lifting/export"
+	"lifting/memory"
+	"lifting/plates"
+	"lifting/program"
+)
+
+const usage = `Usage: lifting [command] [flags]
+
+Run without a command to use the interactive prompts; -profile NAME picks
+the athlete profile to use.
+
+Commands:
+  generate      Generate the program and write it as CSV to stdout
+  export        Generate the program and write it to a CSV file
+  upload        Generate the program and sync it to Hevy
+  import        Fetch logged Hevy workouts and show the AMRAP reps achieved
+  next-cycle    Apply standard training max increases to the saved config
+  history       List, show, diff or roll back saved cycles
+  profile       List, create, delete or rename athlete profiles
+  init-config   Write the config built from flags to a JSON config file
+  init-library  Write the exercise library to a JSON file for editing
+  e1rm          Estimate a 1RM and training max from a weight x reps set
+
+Run 'lifting <command> -h' to see the flags for a command.
+`
+
+// liftFlagNames maps each main lift to the flag used to set its training max
+var liftFlagNames = map[config.Lift]string{
+	config.Squat:    "squat",
+	config.Bench:    "bench",
+	config.Deadlift: "deadlift",
+	config.OHP:      "ohp",
+}
+
+// runCommand dispatches a non-interactive subcommand
+func runCommand(name string, args []string) error {
+	switch name {
+	case "generate":
+		return runGenerate(args)
+	case "export":
+		return runExport(args)
+	case "upload":
+		return runUpload(args)
+	case "import":
+		return runImport(args)
+	case "next-cycle":
+		return runNextCycle(args)
+	case "history":
+		return runHistory(args)
+	case "profile":
+		return runProfile(args)
+	case "init-config":
+		return runInitConfig(args)
+	case "init-library":
+		return runInitLibrary(args)
+	case "e1rm":
+		return runE1RM(args)
+	case "help":
+		fmt.Print(usage)
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// configFlags holds the flags shared by every command that builds a config
+type configFlags struct {
+	fs            *flag.FlagSet
+	maxes         map[config.Lift]*float64
+	template      *string
+	mainWork      *string
+	jokers        *int
+	jokerStep     *float64
+	warmups       *string
+	deloadWarmups *bool
+	units         *string
+	increment     *float64
+	roundMode     *string
+	bar           *float64
+	plateList     *string
+	trueMax       *bool
+	estimates     *string
+	formula       *string
+	customLifts   *string
+	customMaxes   *string
+	order         *string
+	days          *int
+	cycleWeeks    *int
+	deload        *string
+	cycle         *int
+	bbb           *float64
+	bbbWeeks      *string
+	challenge     *string
+	month         *int
+	pairing       *string
+	supplement    *string
+	accessories   *string
+	assistance    *string
+	conditioning  *string
+	leader        *string
+	leaderN       *int
+	seventhWeek   *string
+	anchor        *string
+	anchorN       *int
+	library       *string
+	configFile    *string
+	fromMemory    *bool
+	memoryFile    *string
+	profile       *string
+	save          *bool
+
+	memoryPath string // memory file resolved from -memory or -profile
+}
+
+// newConfigFlags registers the config flags on a flag set
+func newConfigFlags(fs *flag.FlagSet) *configFlags {
+	f := &configFlags{
+		fs:    fs,
+		maxes: make(map[config.Lift]*float64),
+	}
+	for _, lift := range config.AllLifts() {
+		f.maxes[lift] = fs.Float64(liftFlagNames[lift], 0, fmt.Sprintf("%s max (in -units)", lift))
+	}
+	f.template = fs.String("template", config.DefaultTemplate, "program template: "+templateNames())
+	f.mainWork = fs.String("main-work", string(config.MainWork531), "main work style: 531 (AMRAP top set) or 5spro")
+	f.jokers = fs.Int("jokers", 0, "number of Joker sets after the top set on 3s and 1s weeks")
+	f.jokerStep = fs.Float64("joker-step", 5, "percent added per Joker set (5-10)")
+	f.warmups = fs.String("warmups", "", "warm-up sets, e.g. \"bar x10, 30x5, 50x5, 70x3\" or \"none\" for every lift, or per lift: \"squat=40x5,50x5,60x3; bench=none\" (default 40x5, 50x5, 60x3)")
+	f.deloadWarmups = fs.Bool("deload-warmups", false, "also warm up before deload week sets")
+	f.units = fs.String("units", "lbs", "weight unit for maxes and generated weights: lbs or kg")
+	f.increment = fs.Float64("round", 0, "rounding increment, e.g. 5, 2.5 or 1.25 (default: 5 lbs / 2.5 kg)")
+	f.roundMode = fs.String("round-mode", "nearest", "rounding direction: nearest, down or up")
+	f.bar = fs.Float64("bar", 0, "bar weight; enables plate-aware rounding (default: 45 lbs / 20 kg with -plates)")
+	f.plateList = fs.String("plates", "", "available plates per side, e.g. 45x4,25x2,10,5,2.5 (xN = pairs, omit for unlimited) or \"standard\"")
+	f.customLifts = fs.String("lifts", "", "custom main lifts to register, e.g. \"Front Squat:lower,Close-Grip Bench\" (:lower uses the lower body TM increase)")
+	f.customMaxes = fs.String("max", "", "maxes for custom lifts (in -units), e.g. \"Front Squat=225\"")
+	f.trueMax = fs.Bool("true-max", false, "treat lift maxes as true 1RMs and use 90% as the training max")
+	f.estimates = fs.String("estimate", "", "set training maxes from recent weight x reps sets, e.g. \"squat=225x8,bench=185x6\"")
+	f.formula = fs.String("e1rm-formula", string(e1rm.DefaultFormula), "formula for -estimate: wendler, epley or brzycki")
+	f.order = fs.String("order", "", "comma-separated lift order, e.g. squat,bench,deadlift,ohp")
+	f.days = fs.Int("days", 4, "training days per week: 4, 3 (lifts roll over across weeks) or 2 (two lifts per day)")
+	f.cycleWeeks = fs.Int("cycle-weeks", 3, "working weeks per cycle (1-3), from the start of the 5s/3s/1s wave")
+	f.deload = fs.String("deload", "standard", "deload weeks: standard (single cycles only), always, never, or every:N cycles")
+	f.cycle = fs.Int("cycle", 1, "number of the current cycle, used by -deload every:N")
+	f.bbb = fs.Float64("bbb", 50, "BBB percentage of training max")
+	f.bbbWeeks = fs.String("bbb-weeks", "", "per-week BBB percentages, e.g. 50,60,70, or \"beefcake\"")
+	f.challenge = fs.String("bbb-challenge", "", "BBB percentage per cycle for a multi-cycle challenge, e.g. 60,70,80, or \"standard\"")
+	f.month = fs.Int("bbb-challenge-month", 1, "current month of the BBB challenge")
+	f.pairing = fs.String("pairing", "", "BBB pairing: same, opposite, or main=bbb pairs like squat=deadlift,bench=ohp")
+	f.supplement = fs.String("supplemental", "5x5", "FSL/SSL supplemental sets x reps")
+	f.accessories = fs.String("accessories", "", "accessory per main lift, e.g. squat=Leg Curl,bench=Dips")
+	f.assistance = fs.String("assistance", "", "assistance work, e.g. \"squat=Chin-up 5x10; squat=Ab Wheel 5x50-100; bench=Dips 5x10@25 rest=60\" (MIN-MAX is a total-rep target, @WEIGHT in -units, rest in seconds); replaces -accessories for those lifts")
+	f.conditioning = fs.String("conditioning", "", "jumps, throws and conditioning, e.g. \"squat=Box Jump 3x5; squat=Hill Sprints 10x15s; deadlift=Prowler Push 6x40m\" (per set: reps, Ns/Nmin or Nm); replaces those lifts' entries")
+	f.leader = fs.String("leader", "", "block leader template (default: -template)")
+	f.leaderN = fs.Int("leader-cycles", 0, "plan a block with this many leader cycles")
+	f.seventhWeek = fs.String("seventh-week", "", "7th week: deload, tm-test or none; replaces the deload week, or follows the leader cycles of a block (block default: deload)")
+	f.anchor = fs.String("anchor", "", "block anchor template")
+	f.anchorN = fs.Int("anchor-cycles", 1, "block anchor cycles (requires -anchor)")
+	f.library = fs.String("library", config.DefaultLibraryFile, "exercise library file merged over the built-in exercises, if it exists")
+	f.configFile = fs.String("config", "", "load the config from a JSON or YAML (.yaml/.yml) config file; other flags override it")
+	f.fromMemory = fs.Bool("from-memory", false, "start from the saved config; other flags override it")
+	f.memoryFile = fs.String("memory", memory.DefaultFile, "path to the memory file")
+	f.profile = fs.String("profile", "", profileUsage)
+	f.save = fs.Bool("save", false, "save the resulting config to the memory file")
+	return f
+}
+
+// build creates a config from the parsed flags, bypassing the prompts
+func (f *configFlags) build() (*config.Config, error) {
+	set := make(map[string]bool)
+	f.fs.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+
+	if *f.fromMemory && *f.configFile != "" {
+		return nil, fmt.Errorf("-config and -from-memory cannot be used together")
+	}
+	path, err := memoryPath(f.fs, *f.memoryFile, *f.profile)
+	if err != nil {
+		return nil, err
+	}
+	f.memoryPath = path
+
+	// Accessories in configs are checked against the library, so load it first
+	if err := loadLibrary(*f.library); err != nil {
+		return nil, err
+	}
+
+	cfg := config.NewDefaultConfig()
+	if *f.configFile != "" {
+		loaded, err := config.LoadFile(*f.configFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg = loaded
+	}
+	if *f.fromMemory {
+		snapshot, err := memory.Load(f.memoryPath)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot == nil {
+			return nil, fmt.Errorf("no saved configuration found at %s", f.memoryPath)
+		}
+		cfg = memory.CloneConfig(snapshot.Config)
+	}
+
+	if set["template"] {
+		cfg.Template = strings.ToLower(*f.template)
+	}
+
+	if set["main-work"] {
+		cfg.MainWork = config.MainWorkStyle(strings.ToLower(*f.mainWork))
+	}
+	if set["jokers"] {
+		cfg.Jokers.Sets = *f.jokers
+	}
+	if set["jokers"] || set["joker-step"] {
+		cfg.Jokers.Step = *f.jokerStep
+	}
+
+	if set["units"] {
+		unit, err := config.ParseUnit(*f.units)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -units: %w", err)
+		}
+		cfg.Units = unit
+	}
+
+	if set["round"] {
+		cfg.Rounding.Increment = *f.increment
+	}
+	if set["round-mode"] {
+		cfg.Rounding.Mode = config.RoundingMode(strings.ToLower(*f.roundMode))
+	}
+	if set["plates"] || set["bar"] {
+		if err := applyPlates(cfg, *f.plateList, *f.bar); err != nil {
+			return nil, fmt.Errorf("invalid -plates: %w", err)
+		}
+	}
+
+	if set["lifts"] {
+		if err := applyCustomLifts(cfg, *f.customLifts); err != nil {
+			return nil, fmt.Errorf("invalid -lifts: %w", err)
+		}
+	}
+
+	maxes := make(config.LiftMaxes)
+	for _, lift := range config.AllLifts() {
+		if set[liftFlagNames[lift]] {
+			maxes[lift] = *f.maxes[lift]
+		}
+	}
+	if set["max"] {
+		pairs, err := parsePairs(*f.customMaxes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -max: %w", err)
+		}
+		for name, value := range pairs {
+			lift, err := cfg.ParseLift(name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid -max: %w", err)
+			}
+			max, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid -max: invalid weight %q for %s", value, lift)
+			}
+			maxes[lift] = max
+		}
+	}
+	for lift, max := range maxes {
+		if *f.trueMax {
+			max = config.CalculateTrainingMax(max)
+		}
+		cfg.TrainingMaxes[lift] = max
+	}
+	if set["estimate"] {
+		if err := applyEstimates(cfg, *f.estimates, *f.formula); err != nil {
+			return nil, fmt.Errorf("invalid -estimate: %w", err)
+		}
+	}
+
+	if set["order"] {
+		order, err := parseLiftList(cfg, *f.order)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -order: %w", err)
+		}
+		cfg.LiftOrder = order
+	}
+	if set["days"] {
+		cfg.DaysPerWeek = *f.days
+	}
+	if set["cycle-weeks"] {
+		cfg.CycleWeeks = *f.cycleWeeks
+	}
+	if set["deload"] {
+		policy, err := parseDeloadPolicy(*f.deload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -deload: %w", err)
+		}
+		cfg.Deload = policy
+	}
+	if set["cycle"] {
+		cfg.Cycle = *f.cycle
+	}
+
+	if set["warmups"] {
+		if err := applyWarmups(cfg, *f.warmups); err != nil {
+			return nil, fmt.Errorf("invalid -warmups: %w", err)
+		}
+	}
+	if set["deload-warmups"] {
+		cfg.WarmupOnDeload = *f.deloadWarmups
+	}
+
+	if set["bbb"] {
+		cfg.BBBPercentage = *f.bbb
+	}
+
+	if set["bbb-weeks"] {
+		weeks, err := parsePercentages(*f.bbbWeeks, "beefcake", config.BBBBeefcakeWeeks)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -bbb-weeks: %w", err)
+		}
+		cfg.BBBWeekPercentages = weeks
+	}
+
+	if set["bbb-challenge"] {
+		months, err := parsePercentages(*f.challenge, "standard", config.BBBChallengeMonths)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -bbb-challenge: %w", err)
+		}
+		cfg.BBBChallenge = &config.BBBChallenge{Percentages: months, Month: 1}
+	}
+	if set["bbb-challenge-month"] {
+		if cfg.BBBChallenge == nil {
+			return nil, fmt.Errorf("-bbb-challenge-month requires an active BBB challenge")
+		}
+		cfg.BBBChallenge.Month = *f.month
+	}
+
+	if set["pairing"] {
+		if err := applyPairing(cfg, *f.pairing); err != nil {
+			return nil, fmt.Errorf("invalid -pairing: %w", err)
+		}
+	}
+
+	if set["supplemental"] {
+		sets, reps, err := parseSetsReps(*f.supplement)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -supplemental: %w", err)
+		}
+		cfg.Supplemental = config.Supplemental{Sets: sets, Reps: reps}
+	}
+
+	if set["accessories"] {
+		pairs, err := parsePairs(*f.accessories)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -accessories: %w", err)
+		}
+		for name, accessory := range pairs {
+			lift, err := cfg.ParseLift(name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid -accessories: %w", err)
+			}
+			cfg.Accessories[lift] = accessory
+		}
+	}
+
+	if set["leader"] || set["leader-cycles"] || set["anchor"] || set["anchor-cycles"] || (set["seventh-week"] && cfg.Block != nil) {
+		applyBlock(cfg, f, set)
+	} else if set["seventh-week"] {
+		cfg.SeventhWeek = parseSeventhWeek(*f.seventhWeek)
+	}
+
+	if set["assistance"] {
+		if err := applyAssistance(cfg, *f.assistance); err != nil {
+			return nil, fmt.Errorf("invalid -assistance: %w", err)
+		}
+	}
+
+	if set["conditioning"] {
+		if err := applyConditioning(cfg, *f.conditioning); err != nil {
+			return nil, fmt.Errorf("invalid -conditioning: %w", err)
+		}
+	}
+
+	// Fill in defaults for anything the flags left unset, such as the BBB
+	// pairing of a newly ordered custom lift
+	cfg.ApplyDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	return cfg, nil
+}
+
+// finish saves the config to memory when -save was given
+func (f *configFlags) finish(cfg *config.Config) error {
+	if !*f.save {
+		return nil
+	}
+	if err := memory.Save(f.memoryPath, cfg); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Saved program memory to %s\n", f.memoryPath)
+	return nil
+}
+
+// runGenerate writes the generated program as CSV to stdout
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	cf := newConfigFlags(fs)
+	fs.Parse(args)
+
+	cfg, err := cf.build()
+	if err != nil {
+		return err
+	}
+
+	prog, err := program.Generate(cfg)
+	if err != nil {
+		return err
+	}
+	if err := export.WriteCSV(os.Stdout, prog); err != nil {
+		return err
+	}
+	return cf.finish(cfg)
+}
+
+// runExport writes the generated program to a CSV file
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	cf := newConfigFlags(fs)
+	output := fs.String("o", "", "output CSV filename (default: based on the template, e.g. 531_bbb.csv)")
+	fs.Parse(args)
+
+	cfg, err := cf.build()
+	if err != nil {
+		return err
+	}
+
+	prog, err := program.Generate(cfg)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		*output = export.DefaultFilename(prog)
+	}
+	if err := export.ToCSV(prog, *output); err != nil {
+		return err
+	}
+	fmt.Printf("Program exported to %s\n", *output)
+	return cf.finish(cfg)
+}
+
+// runUpload syncs the generated program to Hevy
+func runUpload(args []string) error {
+	fs := flag.NewFlagSet("upload", flag.ExitOnError)
+	cf := newConfigFlags(fs)
+	apiKey := fs.String("api-key", os.Getenv("HEVY_API_KEY"), "Hevy API key (defaults to $HEVY_API_KEY)")
+	fs.Parse(args)
+
+	if *apiKey == "" {
+		return fmt.Errorf("missing Hevy API key (set -api-key or HEVY_API_KEY)")
+	}
+
+	cfg, err := cf.build()
+	if err != nil {
+		return err
+	}
+
+	// Generate refuses invalid configs before any request is made
+	prog, err := program.Generate(cfg)
+	if err != nil {
+		return err
+	}
+	if err := uploadToHevy(*apiKey, cfg, prog); err != nil {
+		return err
+	}
+	return cf.finish(cfg)
+}
+
+// runImport fetches the workouts logged from the config's program and
+// prints the reps achieved on each AMRAP set
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	cf := newConfigFlags(fs)
+	apiKey := fs.String("api-key", os.Getenv("HEVY_API_KEY"), "Hevy API key (defaults to $HEVY_API_KEY)")
+	since := fs.String("since", "", "only fetch workouts changed since this time (RFC 3339 or YYYY-MM-DD)")
+	fs.Parse(args)
+
+	if *apiKey == "" {
+		return fmt.Errorf("missing Hevy API key (set -api-key or HEVY_API_KEY)")
+	}
+	var sinceTime time.Time
+	if *since != "" {
+		var err error
+		if sinceTime, err = parseTime(*since); err != nil {
+			return fmt.Errorf("invalid -since: %w", err)
+		}
+	}
+
+	cfg, err := cf.build()
+	if err != nil {
+		return err
+	}
+	prog, err := program.Generate(cfg)
+	if err != nil {
+		return err
+	}
+
+	results, err := importAMRAPResults(*apiKey, cfg, prog, sinceTime)
+	if err != nil {
+		return err
+	}
+	printAMRAPResults(prog.Units, results)
+	return nil
+}
+
+// parseTime parses an RFC 3339 timestamp or a YYYY-MM-DD date
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(time.DateOnly, value, time.Local)
+}
+
+// runNextCycle applies standard TM increases to the saved config
+func runNextCycle(args []string) error {
+	fs := flag.NewFlagSet("next-cycle", flag.ExitOnError)
+	memoryFile := fs.String("memory", memory.DefaultFile, "path to the memory file")
+	profile := fs.String("profile", "", profileUsage)
+	output := fs.String("o", "", "also export the new cycle to this CSV file")
+	dryRun := fs.Bool("dry-run", false, "print the new training maxes without saving them")
+	tmTest := fs.String("tm-test", "", "7th-week TM test reps made at 100%, e.g. squat=5,bench=3; lifts under 5 reps drop to 90% of their tested TM")
+	amrap := fs.String("amrap", "", "reps made on each lift's 1+ AMRAP set last cycle, e.g. squat=3,bench=0; a missed minimum holds the TM")
+	fromHevy := fs.Bool("from-hevy", false, "import AMRAP reps from workouts logged in Hevy instead of -amrap")
+	apiKey := fs.String("api-key", os.Getenv("HEVY_API_KEY"), "Hevy API key for -from-hevy (defaults to $HEVY_API_KEY)")
+	since := fs.String("since", "", "with -from-hevy, only fetch workouts changed since this time (RFC 3339 or YYYY-MM-DD)")
+	reset := fs.Bool("reset", false, "with -amrap or -from-hevy, reset TMs to 90% of e1RM when it fell far behind")
+	formula := fs.String("e1rm-formula", string(e1rm.DefaultFormula), "formula for -reset: wendler, epley or brzycki")
+	library := fs.String("library", config.DefaultLibraryFile, "exercise library file merged over the built-in exercises, if it exists")
+	fs.Parse(args)
+
+	if *tmTest != "" && (*amrap != "" || *fromHevy) {
+		return fmt.Errorf("-tm-test cannot be combined with -amrap or -from-hevy")
+	}
+	if *amrap != "" && *fromHevy {
+		return fmt.Errorf("-amrap and -from-hevy cannot be used together")
+	}
+	if *fromHevy && *apiKey == "" {
+		return fmt.Errorf("missing Hevy API key (set -api-key or HEVY_API_KEY)")
+	}
+	rules := memory.NewProgressionRules()
+	rules.Reset = *reset
+	var err error
+	if rules.Formula, err = e1rm.ParseFormula(*formula); err != nil {
+		return fmt.Errorf("invalid -e1rm-formula: %w", err)
+	}
+
+	path, err := memoryPath(fs, *memoryFile, *profile)
+	if err != nil {
+		return err
+	}
+	if err := loadLibrary(*library); err != nil {
+		return err
+	}
+	snapshot, err := memory.Load(path)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return fmt.Errorf("no saved configuration found at %s", path)
+	}
+
+	if *tmTest != "" && !snapshot.Config.HasTMTest() {
+		return fmt.Errorf("-tm-test given, but the saved program has no 7th-week TM test")
+	}
+
+	next := memory.NextCycleConfig(snapshot.Config)
+	record := memory.Record{Note: "next cycle"}
+	if *tmTest != "" {
+		reps, err := parseLiftReps(snapshot.Config, *tmTest)
+		if err != nil {
+			return fmt.Errorf("invalid -tm-test: %w", err)
+		}
+		var results []memory.TMTestResult
+		next, results = memory.NextCycleConfigAfterTMTest(snapshot.Config, reps)
+		printTMTestResults(next.Units, results)
+		for _, result := range results {
+			record.Results = append(record.Results, result.Log())
+		}
+	}
+	if *amrap != "" || *fromHevy {
+		prog, err := program.Generate(snapshot.Config)
+		if err != nil {
+			return err
+		}
+
+		var logs []memory.AMRAPLog
+		if *fromHevy {
+			var sinceTime time.Time
+			if *since != "" {
+				if sinceTime, err = parseTime(*since); err != nil {
+					return fmt.Errorf("invalid -since: %w", err)
+				}
+			}
+			imported, err := importAMRAPResults(*apiKey, snapshot.Config, prog, sinceTime)
+			if err != nil {
+				return err
+			}
+			logs = amrapLogsFromHevy(imported)
+		} else {
+			reps, err := parseLiftReps(snapshot.Config, *amrap)
+			if err != nil {
+				return fmt.Errorf("invalid -amrap: %w", err)
+			}
+			logs = amrapLogsFromReps(prog, reps)
+		}
+
+		var results []memory.ProgressionResult
+		if next, results, err = memory.NextCycleConfigFromAMRAP(snapshot.Config, logs, rules); err != nil {
+			return err
+		}
+		printProgression(next.Units, results)
+		record.Results = logs
+	}
+	printTrainingMaxes(next)
+	printBBBChallenge(snapshot.Config, next)
+
+	if *output != "" {
+		prog, err := program.Generate(next)
+		if err != nil {
+			return err
+		}
+		if err := export.ToCSV(prog, *output); err != nil {
+			return err
+		}
+		fmt.Printf("Program exported to %s\n", *output)
+	}
+
+	if *dryRun {
+		return nil
+	}
+	if err := memory.Append(path, next, record); err != nil {
+		return err
+	}
+	fmt.Printf("Saved program memory to %s\n", path)
+	return nil
+}
+
+// historyUsage describes the history actions
+const historyUsage = `Usage: lifting history <action> [flags] [entries]
+
+Actions:
+  list              List every saved cycle
+  show N            Show history entry N (default: the latest)
+  diff A [B]        Show the config changes from entry A to B (default: the latest)
+  rollback N        Make entry N's config current again, recorded as a new entry
+`
+
+// runHistory lists, shows, diffs or rolls back the memory file's history
+func runHistory(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprint(os.Stderr, historyUsage)
+		return fmt.Errorf("missing history action")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("history "+action, flag.ExitOnError)
+	memoryFile := fs.String("memory", memory.DefaultFile, "path to the memory file")
+	profile := fs.String("profile", "", profileUsage)
+	library := fs.String("library", config.DefaultLibraryFile, "exercise library file merged over the built-in exercises, if it exists")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), historyUsage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args[1:])
+
+	ids := make([]int, fs.NArg())
+	for i, arg := range fs.Args() {
+		id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err != nil {
+			return fmt.Errorf("invalid history entry %q", arg)
+		}
+		ids[i] = id
+	}
+
+	path, err := memoryPath(fs, *memoryFile, *profile)
+	if err != nil {
+		return err
+	}
+	// Accessories in saved configs are checked against the library
+	if err := loadLibrary(*library); err != nil {
+		return err
+	}
+	snapshot, err := memory.Load(path)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return fmt.Errorf("no saved configuration found at %s", path)
+	}
+	if len(snapshot.History) == 0 {
+		return fmt.Errorf("%s has no history", path)
+	}
+	latest := snapshot.History[len(snapshot.History)-1].ID
+
+	switch {
+	case action == "list" && len(ids) == 0:
+		for _, entry := range snapshot.History {
+			printHistoryLine(entry)
+		}
+		return nil
+	case action == "show" && len(ids) <= 1:
+		entry, err := snapshot.Entry(optionalID(ids, 0, latest))
+		if err != nil {
+			return err
+		}
+		printHistoryEntry(*entry)
+		return nil
+	case action == "diff" && (len(ids) == 1 || len(ids) == 2):
+		from, err := snapshot.Entry(ids[0])
+		if err != nil {
+			return err
+		}
+		to, err := snapshot.Entry(optionalID(ids, 1, latest))
+		if err != nil {
+			return err
+		}
+		changes, err := memory.DiffConfigs(from.Config, to.Config)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("#%d -> #%d:\n", from.ID, to.ID)
+		if len(changes) == 0 {
+			fmt.Println("  no changes")
+		}
+		for _, change := range changes {
+			fmt.Printf("  %s: %s -> %s\n", change.Field, orUnset(change.From), orUnset(change.To))
+		}
+		return nil
+	case action == "rollback" && len(ids) == 1:
+		entry, err := memory.Rollback(path, ids[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back to #%d as #%d\n", ids[0], entry.ID)
+		printTrainingMaxes(entry.Config)
+		return nil
+	default:
+		fs.Usage()
+		return fmt.Errorf("invalid history action %q with %d entries", action, len(ids))
+	}
+}
+
+// optionalID returns ids[i], or fallback if there are not that many ids
+func optionalID(ids []int, i, fallback int) int {
+	if i < len(ids) {
+		return ids[i]
+	}
+	return fallback
+}
+
+// orUnset labels empty diff values
+func orUnset(value string) string {
+	if value == "" {
+		return "(unset)"
+	}
+	return value
+}
+
+// printHistoryLine prints a one-line summary of a history entry
+func printHistoryLine(entry memory.Entry) {
+	cfg := entry.Config
+	note := ""
+	if entry.Note != "" {
+		note = " (" + entry.Note + ")"
+	}
+	fmt.Printf("#%-3d %s  cycle %d  %s%s: %s\n", entry.ID, entry.SavedAt.Local().Format("2006-01-02 15:04"), cfg.Cycle, cfg.Template, note, summarizeTrainingMaxes(cfg))
+}
+
+// summarizeTrainingMaxes lists a config's training maxes on one line
+func summarizeTrainingMaxes(cfg *config.Config) string {
+	maxes := make([]string, 0, len(cfg.Lifts()))
+	for _, lift := range cfg.Lifts() {
+		maxes = append(maxes, fmt.Sprintf("%s %s", lift, cfg.Units.Format(cfg.TrainingMaxes[lift])))
+	}
+	return strings.Join(maxes, ", ")
+}
+
+// printHistoryEntry prints everything recorded for a history entry
+func printHistoryEntry(entry memory.Entry) {
+	cfg := entry.Config
+	fmt.Printf("Entry #%d\n", entry.ID)
+	fmt.Printf("Saved: %s\n", entry.SavedAt.Local().Format(time.RFC1123))
+	if entry.Note != "" {
+		fmt.Printf("Note: %s\n", entry.Note)
+	}
+	fmt.Printf("Cycle: %d\n", cfg.Cycle)
+	fmt.Printf("Template: %s\n", cfg.Template)
+	if entry.ProgramHash != "" {
+		fmt.Printf("Program: %s\n", entry.ProgramHash)
+	}
+	printTrainingMaxes(cfg)
+	if len(entry.TMChanges) > 0 {
+		fmt.Println("Training max changes:")
+		for _, change := range entry.TMChanges {
+			fmt.Printf("  %s: %s -> %s\n", change.Lift, cfg.Units.Format(change.From), cfg.Units.Format(change.To))
+		}
+	}
+	if len(entry.Results) > 0 {
+		fmt.Println("Logged results:")
+		for _, result := range entry.Results {
+			fmt.Printf("  %s: %s x%d (%d+ at %.0f%%)\n", result.Lift, cfg.Units.Format(result.Weight), result.Reps, result.MinReps, result.Percentage)
+		}
+	}
+}
+
+// profileUsage is the help for the -profile flag
+const profileUsage = "athlete profile whose memory file to use instead of -memory (see 'lifting profile')"
+
+// memoryPath resolves the memory file from -memory and -profile. A profile
+// must already exist; new ones are made with 'lifting profile create'.
+func memoryPath(fs *flag.FlagSet, file, profile string) (string, error) {
+	if profile == "" {
+		return file, nil
+	}
+	memorySet := false
+	fs.Visit(func(fl *flag.Flag) { memorySet = memorySet || fl.Name == "memory" })
+	if memorySet {
+		return "", fmt.Errorf("-memory and -profile cannot be used together")
+	}
+
+	exists, err := memory.ProfileExists(profile)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("profile %q does not exist (create it with 'lifting profile create %s')", profile, profile)
+	}
+	return memory.ProfilePath(profile)
+}
+
+// profileCommandUsage describes the profile actions
+const profileCommandUsage = `Usage: lifting profile <action> [names] [flags]
+
+Actions:
+  list              List every profile with its current training maxes
+  create NAME       Create a profile from the config flags, e.g. -squat 315 or
+                    -from-memory to copy the memory file or another -profile
+  delete NAME       Delete a profile along with its history
+  rename OLD NEW    Rename a profile
+
+Each profile has its own config and history, stored in lifting/profiles
+under the user config directory (os.UserConfigDir: $XDG_CONFIG_HOME or
+~/.config on Linux, ~/Library/Application Support on macOS, %AppData% on
+Windows).
+`
+
+// runProfile lists, creates, deletes or renames athlete profiles
+func runProfile(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprint(os.Stderr, profileCommandUsage)
+		return fmt.Errorf("missing profile action")
+	}
+	action, args := args[0], args[1:]
+
+	// Names come before any flags
+	var names []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		names, args = append(names, args[0]), args[1:]
+	}
+
+	fs := flag.NewFlagSet("profile "+action, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), profileCommandUsage)
+		if dir, err := memory.ProfileDir(); err == nil {
+			fmt.Fprintf(fs.Output(), "Profiles directory: %s\n\n", dir)
+		}
+		fs.PrintDefaults()
+	}
+
+	switch {
+	case action == "list" && len(names) == 0:
+		library := fs.String("library", config.DefaultLibraryFile, "exercise library file merged over the built-in exercises, if it exists")
+		fs.Parse(args)
+		// Accessories in saved configs are checked against the library
+		if err := loadLibrary(*library); err != nil {
+			return err
+		}
+		return listProfiles()
+	case action == "create" && len(names) == 1:
+		cf := newConfigFlags(fs)
+		fs.Parse(args)
+		if err := memory.ValidateProfileName(names[0]); err != nil {
+			return err
+		}
+		cfg, err := cf.build()
+		if err != nil {
+			return err
+		}
+		path, err := memory.CreateProfile(names[0], cfg, memory.Record{Note: "profile created"})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created profile %s at %s\n", names[0], path)
+		printTrainingMaxes(cfg)
+		return nil
+	case action == "delete" && len(names) == 1:
+		fs.Parse(args)
+		if err := memory.DeleteProfile(names[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted profile %s\n", names[0])
+		return nil
+	case action == "rename" && len(names) == 2:
+		fs.Parse(args)
+		if err := memory.RenameProfile(names[0], names[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Renamed profile %s to %s\n", names[0], names[1])
+		return nil
+	default:
+		fs.Usage()
+		return fmt.Errorf("invalid profile action %q with %d names", action, len(names))
+	}
+}
+
+// listProfiles prints each profile with its latest saved config
+func listProfiles() error {
+	dir, err := memory.ProfileDir()
+	if err != nil {
+		return err
+	}
+	names, err := memory.Profiles()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Printf("No profiles in %s\n", dir)
+		return nil
+	}
+
+	fmt.Printf("Profiles in %s:\n", dir)
+	for _, name := range names {
+		path, err := memory.ProfilePath(name)
+		if err != nil {
+			return err
+		}
+		snapshot, err := memory.Load(path)
+		if err != nil {
+			fmt.Printf("  %-12s %v\n", name, err)
+			continue
+		}
+		cfg := snapshot.Config
+		fmt.Printf("  %-12s %s  cycle %d  %s: %s\n", name, snapshot.SavedAt.Local().Format("2006-01-02"), cfg.Cycle, cfg.Template, summarizeTrainingMaxes(cfg))
+	}
+	return nil
+}
+
+// runInitConfig writes the config built from flags to a config file
+func runInitConfig(args []string) error {
+	fs := flag.NewFlagSet("init-config", flag.ExitOnError)
+	cf := newConfigFlags(fs)
+	output := fs.String("o", "531.json", "output config filename")
+	fs.Parse(args)
+
+	cfg, err := cf.build()
+	if err != nil {
+		return err
+	}
+
+	if err := config.WriteFile(*output, cfg); err != nil {
+		return err
+	}
+	fmt.Printf("Config written to %s\n", *output)
+	return cf.finish(cfg)
+}
+
+// runInitLibrary writes the exercise library to a file for editing
+func runInitLibrary(args []string) error {
+	fs := flag.NewFlagSet("init-library", flag.ExitOnError)
+	library := fs.String("library", config.DefaultLibraryFile, "existing exercise library to include, if it exists")
+	output := fs.String("o", config.DefaultLibraryFile, "output library filename")
+	fs.Parse(args)
+
+	if err := loadLibrary(*library); err != nil {
+		return err
+	}
+	if err := config.WriteLibrary(*output, config.ActiveLibrary()); err != nil {
+		return err
+	}
+	fmt.Printf("Exercise library written to %s\n", *output)
+	return nil
+}
+
+// runE1RM prints the estimated 1RM and training max for a weight x reps set
+// under every formula
+func runE1RM(args []string) error {
+	fs := flag.NewFlagSet("e1rm", flag.ExitOnError)
+	units := fs.String("units", "lbs", "weight unit: lbs or kg")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: lifting e1rm [flags] WEIGHTxREPS")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one WEIGHTxREPS set, e.g. 225x8")
+	}
+	unit, err := config.ParseUnit(*units)
+	if err != nil {
+		return fmt.Errorf("invalid -units: %w", err)
+	}
+	result, err := e1rm.ParseResult(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s x %d:\n", unit.Format(result.Weight), result.Reps)
+	for _, formula := range e1rm.Formulas {
+		estimate, err := formula.Estimate(result)
+		if err != nil {
+			fmt.Printf("  %-8s %v\n", formula, err)
+			continue
+		}
+		fmt.Printf("  %-8s e1RM %s, training max %s\n", formula, unit.Format(estimate), unit.Format(config.CalculateTrainingMax(estimate)))
+	}
+	return nil
+}
+
+// templateNames lists the registered template names for flag help
+func templateNames() string {
+	var names []string
+	for _, t := range program.Templates() {
+		names = append(names, t.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+// parseLiftList parses a comma-separated list of lift names
+func parseLiftList(cfg *config.Config, value string) ([]config.Lift, error) {
+	var lifts []config.Lift
+	for _, name := range strings.Split(value, ",") {
+		lift, err := cfg.ParseLift(name)
+		if err != nil {
+			return nil, err
+		}
+		lifts = append(lifts, lift)
+	}
+	return lifts, nil
+}
+
+// parsePercentages parses a comma-separated percentage list, or returns
+// preset when value is presetName
+func parsePercentages(value, presetName string, preset []float64) ([]float64, error) {
+	if strings.EqualFold(strings.TrimSpace(value), presetName) {
+		return append([]float64{}, preset...), nil
+	}
+
+	var percentages []float64
+	for _, item := range strings.Split(value, ",") {
+		pct, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid percentage %q", item)
+		}
+		percentages = append(percentages, pct)
+	}
+	return percentages, nil
+}
+
+// parseSetsReps parses a sets x reps prescription such as "5x5"
+func parseSetsReps(value string) (int, int, error) {
+	setsStr, repsStr, ok := strings.Cut(strings.ToLower(value), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected SETSxREPS, got %q", value)
+	}
+	sets, err := strconv.Atoi(strings.TrimSpace(setsStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid sets in %q", value)
+	}
+	reps, err := strconv.Atoi(strings.TrimSpace(repsStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid reps in %q", value)
+	}
+	return sets, reps, nil
+}
+
+// parseLiftReps parses comma-separated lift=reps pairs
+func parseLiftReps(cfg *config.Config, value string) (map[config.Lift]int, error) {
+	pairs, err := parsePairs(value)
+	if err != nil {
+		return nil, err
+	}
+	reps := make(map[config.Lift]int, len(pairs))
+	for name, count := range pairs {
+		lift, err := cfg.ParseLift(name)
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid reps %q for %s", count, lift)
+		}
+		reps[lift] = n
+	}
+	return reps, nil
+}
+
+// parsePairs parses comma-separated key=value pairs
+func parsePairs(value string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected key=value, got %q", item)
+		}
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return pairs, nil
+}
+
+// applyPlates sets the plate inventory from a plate list and bar weight,
+// falling back to the standard gym setup for the config's unit
+func applyPlates(cfg *config.Config, list string, bar float64) error {
+	inventory := plates.Standard(string(cfg.Units))
+	if cfg.Plates != nil {
+		inventory = *cfg.Plates
+	}
+
+	if list != "" && list != "standard" {
+		parsed, err := plates.ParseList(list)
+		if err != nil {
+			return err
+		}
+		inventory.Plates = parsed
+	}
+	if bar > 0 {
+		inventory.BarWeight = bar
+	}
+
+	cfg.Plates = &inventory
+	return nil
+}
+
+// applyBlock sets the leader/anchor block from the block flags, starting
+// from any block already in the config
+func applyBlock(cfg *config.Config, f *configFlags, set map[string]bool) {
+	block := config.Block{
+		LeaderTemplate: cfg.Template,
+		LeaderCycles:   2,
+		SeventhWeek:    config.SeventhWeekDeload,
+	}
+	if cfg.Block != nil {
+		block = *cfg.Block
+	}
+
+	if set["leader"] {
+		block.LeaderTemplate = strings.ToLower(*f.leader)
+	}
+	if set["leader-cycles"] {
+		block.LeaderCycles = *f.leaderN
+	}
+	if set["seventh-week"] {
+		block.SeventhWeek = parseSeventhWeek(*f.seventhWeek)
+	}
+	if set["anchor"] {
+		block.AnchorTemplate = strings.ToLower(*f.anchor)
+		if !set["anchor-cycles"] && block.AnchorCycles == 0 {
+			block.AnchorCycles = *f.anchorN
+		}
+	}
+	if set["anchor-cycles"] {
+		block.AnchorCycles = *f.anchorN
+	}
+
+	cfg.Block = &block
+}
+
+// parseDeloadPolicy parses "standard", "always", "never" or "every:N"
+func parseDeloadPolicy(value string) (config.DeloadPolicy, error) {
+	mode, every, hasEvery := strings.Cut(strings.ToLower(strings.TrimSpace(value)), ":")
+	switch config.DeloadMode(mode) {
+	case "standard":
+		return config.DeloadPolicy{Mode: config.DeloadStandard}, nil
+	case config.DeloadAlways, config.DeloadNever:
+		return config.DeloadPolicy{Mode: config.DeloadMode(mode)}, nil
+	case config.DeloadEvery:
+		if !hasEvery {
+			return config.DeloadPolicy{}, fmt.Errorf("expected every:N, got %q", value)
+		}
+		n, err := strconv.Atoi(every)
+		if err != nil {
+			return config.DeloadPolicy{}, fmt.Errorf("invalid cycle count in %q", value)
+		}
+		return config.DeloadPolicy{Mode: config.DeloadEvery, Every: n}, nil
+	default:
+		return config.DeloadPolicy{}, fmt.Errorf("unknown deload policy %q", value)
+	}
+}
+
+// parseSeventhWeek parses a 7th-week protocol flag, where "none" means no 7th week
+func parseSeventhWeek(value string) config.SeventhWeek {
+	protocol := strings.ToLower(strings.TrimSpace(value))
+	if protocol == "none" {
+		return config.SeventhWeekNone
+	}
+	return config.SeventhWeek(protocol)
+}
+
+// applyAssistance sets the assistance work from semicolon-separated
+// "lift=Exercise SETSxREPS[@WEIGHT] [rest=SECONDS]" items (see
+// config.ParseAssistance). Every lift named replaces its existing assistance.
+func applyAssistance(cfg *config.Config, value string) error {
+	work := make(map[config.Lift][]config.Assistance)
+	for _, item := range strings.Split(value, ";") {
+		name, spec, ok := strings.Cut(item, "=")
+		if !ok {
+			return fmt.Errorf("expected lift=Exercise SETSxREPS, got %q", item)
+		}
+		lift, err := cfg.ParseLift(name)
+		if err != nil {
+			return err
+		}
+		a, err := config.ParseAssistance(spec)
+		if err != nil {
+			return err
+		}
+		work[lift] = append(work[lift], a)
+	}
+
+	if cfg.Assistance == nil {
+		cfg.Assistance = make(map[config.Lift][]config.Assistance)
+	}
+	for lift, list := range work {
+		cfg.Assistance[lift] = list
+	}
+	return nil
+}
+
+// applyEstimates sets training maxes from comma-separated lift=WEIGHTxREPS
+// results using the named e1RM formula
+func applyEstimates(cfg *config.Config, value, formulaName string) error {
+	formula, err := e1rm.ParseFormula(formulaName)
+	if err != nil {
+		return err
+	}
+	pairs, err := parsePairs(value)
+	if err != nil {
+		return err
+	}
+	for name, value := range pairs {
+		lift, err := cfg.ParseLift(name)
+		if err != nil {
+			return err
+		}
+		result, err := e1rm.ParseResult(value)
+		if err != nil {
+			return err
+		}
+		if cfg.TrainingMaxes[lift], err = config.EstimateTrainingMax(result, formula); err != nil {
+			return fmt.Errorf("%s: %w", lift, err)
+		}
+	}
+	return nil
+}
+
+// applyWarmups sets warm-ups from semicolon-separated entries. An entry
+// without a "lift=" prefix applies to every lift.
+func applyWarmups(cfg *config.Config, value string) error {
+	if cfg.Warmups == nil {
+		cfg.Warmups = make(map[config.Lift][]config.WarmupSet)
+	}
+	for _, item := range strings.Split(value, ";") {
+		lifts := cfg.Lifts()
+		spec := item
+		if name, rest, ok := strings.Cut(item, "="); ok {
+			lift, err := cfg.ParseLift(name)
+			if err != nil {
+				return err
+			}
+			lifts, spec = []config.Lift{lift}, rest
+		}
+
+		sets, err := config.ParseWarmup(spec)
+		if err != nil {
+			return err
+		}
+		for _, lift := range lifts {
+			cfg.Warmups[lift] = sets
+		}
+	}
+	return nil
+}
+
+// applyConditioning sets the jumps, throws and conditioning from
+// semicolon-separated "lift=Exercise SETSxAMOUNT" entries, where AMOUNT is
+// reps ("5"), a duration ("15s", "2min") or a distance in meters ("40m").
+// The kind comes from the exercise library. Every lift named replaces its
+// existing entries.
+func applyConditioning(cfg *config.Config, value string) error {
+	work := make(map[config.Lift][]config.Conditioning)
+	for _, item := range strings.Split(value, ";") {
+		name, spec, ok := strings.Cut(item, "=")
+		if !ok {
+			return fmt.Errorf("expected lift=Exercise SETSxAMOUNT, got %q", item)
+		}
+		lift, err := cfg.ParseLift(name)
+		if err != nil {
+			return err
+		}
+
+		spec = strings.TrimSpace(spec)
+		split := strings.LastIndex(spec, " ")
+		if split < 0 {
+			return fmt.Errorf("expected Exercise SETSxAMOUNT, got %q", spec)
+		}
+		exercise, prescription := strings.TrimSpace(spec[:split]), spec[split+1:]
+		entry := config.Conditioning{Exercise: exercise, Kind: config.ConditioningOf(exercise)}
+
+		setsStr, amount, ok := strings.Cut(strings.ToLower(prescription), "x")
+		if !ok {
+			return fmt.Errorf("expected SETSxAMOUNT, got %q", prescription)
+		}
+		if entry.Sets, err = strconv.Atoi(setsStr); err != nil {
+			return fmt.Errorf("invalid sets in %q", prescription)
+		}
+		switch {
+		case strings.HasSuffix(amount, "min"):
+			var minutes int
+			minutes, err = strconv.Atoi(strings.TrimSuffix(amount, "min"))
+			entry.Duration = minutes * 60
+		case strings.HasSuffix(amount, "s"):
+			entry.Duration, err = strconv.Atoi(strings.TrimSuffix(amount, "s"))
+		case strings.HasSuffix(amount, "m"):
+			entry.Distance, err = strconv.ParseFloat(strings.TrimSuffix(amount, "m"), 64)
+		default:
+			entry.Reps, err = strconv.Atoi(amount)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid amount in %q", prescription)
+		}
+
+		work[lift] = append(work[lift], entry)
+	}
+
+	if cfg.Conditioning == nil {
+		cfg.Conditioning = make(map[config.Lift][]config.Conditioning)
+	}
+	for lift, list := range work {
+		cfg.Conditioning[lift] = list
+	}
+	return nil
+}
+
+// applyCustomLifts registers custom lifts from a comma-separated list of
+// names, each optionally suffixed with ":lower". Lifts the config already
+// has are left as they are.
+func applyCustomLifts(cfg *config.Config, value string) error {
+	for _, item := range strings.Split(value, ",") {
+		name, kind, _ := strings.Cut(item, ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("empty lift name in %q", value)
+		}
+		if _, err := cfg.ParseLift(name); err == nil {
+			continue
+		}
+
+		custom := config.CustomLift{Name: config.Lift(name)}
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "lower":
+			custom.LowerBody = true
+		case "", "upper":
+		default:
+			return fmt.Errorf("expected :lower or :upper after %s, got %q", name, kind)
+		}
+		cfg.CustomLifts = append(cfg.CustomLifts, custom)
+	}
+	return nil
+}
+
+// applyPairing sets the BBB pairing from "same", "opposite", or explicit pairs
+func applyPairing(cfg *config.Config, value string) error {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "same":
+		for _, lift := range cfg.LiftOrder {
+			cfg.BBBPairing[lift] = lift
+		}
+		return nil
+	case "opposite":
+		for _, lift := range cfg.LiftOrder {
+			cfg.BBBPairing[lift] = cfg.OppositeLift(lift)
+		}
+		return nil
+	}
+
+	pairs, err := parsePairs(value)
+	if err != nil {
+		return err
+	}
+	for mainName, bbbName := range pairs {
+		mainLift, err := cfg.ParseLift(mainName)
+		if err != nil {
+			return err
+		}
+		bbbLift, err := cfg.ParseLift(bbbName)
+		if err != nil {
+			return err
+		}
+		cfg.BBBPairing[mainLift] = bbbLift
+	}
+	return nil
+}
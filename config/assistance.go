@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AssistanceCategory groups assistance work per the 5/3/1 guidelines
+type AssistanceCategory string
+
+const (
+	AssistancePush          AssistanceCategory = "push"
+	AssistancePull          AssistanceCategory = "pull"
+	AssistanceSingleLegCore AssistanceCategory = "single-leg/core"
+)
+
+// AssistanceCategories lists the categories in programming order
+var AssistanceCategories = []AssistanceCategory{AssistancePush, AssistancePull, AssistanceSingleLegCore}
+
+// CategoryOf returns the library category of an exercise, or "" if the
+// exercise is not in the active library
+func CategoryOf(exercise string) AssistanceCategory {
+	if ex, ok := ActiveLibrary().Lookup(exercise); ok {
+		return ex.Category
+	}
+	return ""
+}
+
+// RepTarget is a total-rep goal spread across an exercise's sets, e.g. 50-100
+type RepTarget struct {
+	Min int
+	Max int
+}
+
+// Assistance is one assistance exercise prescribed on a main lift's day
+type Assistance struct {
+	Exercise  string
+	Category  AssistanceCategory // optional
+	Sets      int                // sets, or the sets a total-rep target is spread over
+	Reps      int                // reps per set; ignored with TotalReps
+	TotalReps *RepTarget         // optional total-rep target across all sets
+	Weight    float64            // optional load in the config's unit (0 = none)
+	Rest      int                // optional rest between sets in seconds
+}
+
+// AssistanceFor returns the assistance work for a lift's day: the configured
+// Assistance list, or the lift's single accessory at its library default
+// prescription (5x10 if it has none)
+func (c *Config) AssistanceFor(lift Lift) []Assistance {
+	if work, ok := c.Assistance[lift]; ok {
+		return work
+	}
+	accessory := c.Accessories[lift]
+	if accessory == "" {
+		return nil
+	}
+	a := Assistance{Exercise: accessory, Sets: 5, Reps: 10}
+	if ex, ok := ActiveLibrary().Lookup(accessory); ok && ex.Sets > 0 && ex.Reps > 0 {
+		a.Sets, a.Reps = ex.Sets, ex.Reps
+	}
+	return []Assistance{a}
+}
+
+// ParseAssistance parses an assistance prescription such as "Dips 5x10",
+// "Chin-up 5x50-100" or "Dips 5x10@25 rest=60": exercise name, then
+// SETSxREPS where REPS may be a MIN-MAX total-rep target, an optional
+// @WEIGHT in the config's unit and an optional rest=SECONDS between sets
+func ParseAssistance(spec string) (Assistance, error) {
+	fields := strings.Fields(spec)
+	var a Assistance
+	if n := len(fields); n > 0 && strings.HasPrefix(strings.ToLower(fields[n-1]), "rest=") {
+		rest := strings.TrimSuffix(strings.ToLower(fields[n-1][len("rest="):]), "s")
+		var err error
+		if a.Rest, err = strconv.Atoi(rest); err != nil || a.Rest < 0 {
+			return a, fmt.Errorf("invalid rest in %q, expected rest=SECONDS", fields[n-1])
+		}
+		fields = fields[:n-1]
+	}
+	if len(fields) < 2 {
+		return a, fmt.Errorf("expected Exercise SETSxREPS[@WEIGHT] [rest=SECONDS], got %q", spec)
+	}
+	a.Exercise = strings.Join(fields[:len(fields)-1], " ")
+	a.Category = CategoryOf(a.Exercise)
+
+	prescription := strings.ToLower(fields[len(fields)-1])
+	prescription, weight, hasWeight := strings.Cut(prescription, "@")
+	if hasWeight {
+		var err error
+		if a.Weight, err = strconv.ParseFloat(weight, 64); err != nil || a.Weight < 0 {
+			return a, fmt.Errorf("invalid weight in %q", fields[len(fields)-1])
+		}
+	}
+
+	setsStr, repsStr, ok := strings.Cut(prescription, "x")
+	if !ok {
+		return a, fmt.Errorf("expected SETSxREPS, got %q", prescription)
+	}
+	var err error
+	if a.Sets, err = strconv.Atoi(setsStr); err != nil {
+		return a, fmt.Errorf("invalid sets in %q", prescription)
+	}
+	if lo, hi, isTarget := strings.Cut(repsStr, "-"); isTarget {
+		target := &RepTarget{}
+		if target.Min, err = strconv.Atoi(lo); err == nil {
+			target.Max, err = strconv.Atoi(hi)
+		}
+		if err != nil {
+			return a, fmt.Errorf("invalid total-rep target in %q", prescription)
+		}
+		a.TotalReps = target
+	} else if a.Reps, err = strconv.Atoi(repsStr); err != nil {
+		return a, fmt.Errorf("invalid reps in %q", prescription)
+	}
+	return a, nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseAssistance checks assistance prescriptions parse with and without
+// their optional weight and rest
+func TestParseAssistance(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Assistance
+	}{
+		{"Dips 5x10", Assistance{Exercise: "Dips", Category: AssistancePush, Sets: 5, Reps: 10}},
+		{"Ab Wheel 5x50-100", Assistance{Exercise: "Ab Wheel", Category: AssistanceSingleLegCore, Sets: 5, TotalReps: &RepTarget{Min: 50, Max: 100}}},
+		{"Dips 5x10@25", Assistance{Exercise: "Dips", Category: AssistancePush, Sets: 5, Reps: 10, Weight: 25}},
+		{"Dips 5x10 rest=60", Assistance{Exercise: "Dips", Category: AssistancePush, Sets: 5, Reps: 10, Rest: 60}},
+		{" Leg Curl 3X12@22.5 REST=90s ", Assistance{Exercise: "Leg Curl", Category: AssistanceSingleLegCore, Sets: 3, Reps: 12, Weight: 22.5, Rest: 90}},
+		{"Chin-up 5x50-100@10 rest=120", Assistance{Exercise: "Chin-up", Category: AssistancePull, Sets: 5, TotalReps: &RepTarget{Min: 50, Max: 100}, Weight: 10, Rest: 120}},
+	}
+	for _, tt := range tests {
+		got, err := ParseAssistance(tt.spec)
+		if err != nil {
+			t.Errorf("ParseAssistance(%q): %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAssistance(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "Dips", "5x10", "Dips 5", "Dips 5x10@heavy", "Dips 5x10@-5", "Dips 5x10 rest=soon", "Dips 5x10 rest=-1", "Dips 5x10-"} {
+		if _, err := ParseAssistance(spec); err == nil {
+			t.Errorf("ParseAssistance(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+// ConditioningKind is the type of a conditioning entry, which decides where
+// it goes in the day
+type ConditioningKind string
+
+const (
+	ConditioningJumps  ConditioningKind = "jumps"        // before the main lift
+	ConditioningThrows ConditioningKind = "throws"       // before the main lift
+	ConditioningCardio ConditioningKind = "conditioning" // at the end of the day
+)
+
+// Conditioning is one jumps, throws or conditioning entry on a main lift's
+// day. Each set is prescribed by reps, a duration, a distance, or a mix.
+type Conditioning struct {
+	Exercise string
+	Kind     ConditioningKind
+	Sets     int
+	Reps     int     // optional reps per set
+	Duration int     // optional seconds per set
+	Distance float64 // optional meters per set
+}
+
+// ConditioningOf returns the library conditioning kind of an exercise,
+// defaulting to conditioning for exercises the library doesn't classify
+func ConditioningOf(exercise string) ConditioningKind {
+	if ex, ok := ActiveLibrary().Lookup(exercise); ok && ex.Conditioning != "" {
+		return ex.Conditioning
+	}
+	return ConditioningCardio
+}
+
+// BeforeMainWork reports whether the entry is done before the main lift
+func (c Conditioning) BeforeMainWork() bool {
+	return c.Kind == ConditioningJumps || c.Kind == ConditioningThrows
+}
@@ -1,6 +1,17 @@
 package config
 
-// Lift represents one of the four main lifts
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lifting/e1rm"
+	"lifting/plates"
+)
+
+// Lift represents a main lift: one of the standard four or a custom lift
 type Lift string
 
 const (
@@ -10,6 +21,222 @@ const (
 	OHP      Lift = "Overhead Press"
 )
 
+// Unit is the weight unit a config's maxes and generated weights are in
+type Unit string
+
+const (
+	Pounds    Unit = "lbs"
+	Kilograms Unit = "kg"
+)
+
+// ParseUnit resolves a unit from common spellings ("lb", "lbs", "kg", ...)
+func ParseUnit(name string) (Unit, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "lb", "lbs", "pound", "pounds":
+		return Pounds, nil
+	case "kg", "kgs", "kilo", "kilos", "kilogram", "kilograms":
+		return Kilograms, nil
+	}
+	return "", fmt.Errorf("unknown unit %q", name)
+}
+
+// Format renders a weight with its unit, e.g. "102.5 kg" or "285 lbs"
+func (u Unit) Format(weight float64) string {
+	return strconv.FormatFloat(math.Round(weight*10)/10, 'f', -1, 64) + " " + string(u)
+}
+
+// RoundingIncrement returns the smallest plate-loadable jump for the unit
+// (5 lbs or 2.5 kg, i.e. the smallest common plate on each side)
+func (u Unit) RoundingIncrement() float64 {
+	if u == Kilograms {
+		return 2.5
+	}
+	return 5
+}
+
+// CycleIncrement returns the standard 5/3/1 training max increase for a lift:
+// 10 lbs / 5 kg for lower body lifts, 5 lbs / 2.5 kg for upper body lifts
+func CycleIncrement(lift Lift, unit Unit) float64 {
+	return cycleIncrement(lift == Squat || lift == Deadlift, unit)
+}
+
+// cycleIncrement returns the standard increase for a lower or upper body lift
+func cycleIncrement(lower bool, unit Unit) float64 {
+	switch {
+	case unit == Kilograms && lower:
+		return 5
+	case unit == Kilograms:
+		return 2.5
+	case lower:
+		return 10
+	default:
+		return 5
+	}
+}
+
+// RoundingMode controls which way generated weights are rounded
+type RoundingMode string
+
+const (
+	RoundNearest RoundingMode = "nearest"
+	RoundDown    RoundingMode = "down" // conservative: never heavier than prescribed
+	RoundUp      RoundingMode = "up"
+)
+
+// Rounding is the policy for turning percentages of a TM into bar weights
+type Rounding struct {
+	// Increment to round to (e.g. 5 lbs, 2.5 or 1.25 kg); 0 uses the unit default
+	Increment float64
+
+	// Direction to round (default nearest)
+	Mode RoundingMode
+}
+
+// Round rounds weight to the policy's increment in the given unit
+func (r Rounding) Round(weight float64, unit Unit) float64 {
+	increment := r.Increment
+	if increment <= 0 {
+		increment = unit.RoundingIncrement()
+	}
+
+	// Small tolerance so e.g. 85% of 300 (254.99999...) still rounds down to 255
+	steps := weight / increment
+	switch r.Mode {
+	case RoundDown:
+		steps = math.Floor(steps + 1e-9)
+	case RoundUp:
+		steps = math.Ceil(steps - 1e-9)
+	default:
+		steps = math.Round(steps)
+	}
+	return steps * increment
+}
+
+// DefaultTemplate is the program template used when none is configured
+const DefaultTemplate = "bbb"
+
+// templateNames holds the template names Validate accepts. config can't
+// import program, so program.Register adds each template here.
+var templateNames = make(map[string]bool)
+
+// RegisterTemplate makes a program template name valid for Template,
+// Block.LeaderTemplate and Block.AnchorTemplate
+func RegisterTemplate(name string) {
+	templateNames[strings.ToLower(name)] = true
+}
+
+// TemplateNames returns the registered template names sorted
+func TemplateNames() []string {
+	names := make([]string, 0, len(templateNames))
+	for name := range templateNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MainWorkStyle selects how the main lift working sets are prescribed
+type MainWorkStyle string
+
+const (
+	MainWork531   MainWorkStyle = "531"   // standard 5/3/1 with an AMRAP top set
+	MainWork5sPro MainWorkStyle = "5spro" // every working set x5, no AMRAP
+)
+
+// DeloadMode selects when a cycle ends with a deload week
+type DeloadMode string
+
+const (
+	DeloadStandard DeloadMode = ""       // single cycles deload; block cycles don't (the 7th week replaces it)
+	DeloadAlways   DeloadMode = "always" // every cycle, including block cycles
+	DeloadNever    DeloadMode = "never"
+	DeloadEvery    DeloadMode = "every" // every Nth cycle, counted by Config.Cycle
+)
+
+// DeloadPolicy configures deload weeks
+type DeloadPolicy struct {
+	Mode  DeloadMode
+	Every int // with DeloadEvery, deload after cycles that are a multiple of Every
+}
+
+// Jokers configures optional Joker sets after the top set on 3s and 1s weeks
+type Jokers struct {
+	Sets int     // number of Joker sets (0 disables them)
+	Step float64 // percentage points added per Joker set (5-10)
+}
+
+// BBBChallenge tracks a multi-cycle BBB progression such as the 3-month
+// challenge, where each cycle (month) uses a heavier BBB percentage
+type BBBChallenge struct {
+	Percentages []float64 // BBB percentage for each month, in order
+	Month       int       // current month, starting at 1
+}
+
+// Current returns the BBB percentage for the current month
+func (b *BBBChallenge) Current() float64 {
+	return b.Percentages[b.Month-1]
+}
+
+// BBBBeefcakeWeeks is the BBB Beefcake per-week progression
+var BBBBeefcakeWeeks = []float64{50, 60, 70}
+
+// BBBChallengeMonths is the 3-month BBB challenge progression
+var BBBChallengeMonths = []float64{60, 70, 80}
+
+// SeventhWeek selects the 5/3/1 Forever 7th-week protocol
+type SeventhWeek string
+
+const (
+	SeventhWeekNone   SeventhWeek = ""
+	SeventhWeekDeload SeventhWeek = "deload"
+	SeventhWeekTMTest SeventhWeek = "tm-test"
+)
+
+// TMTestReps is the number of reps a lift must make at 100% of its training
+// max to pass the 7th-week TM test
+const TMTestReps = 5
+
+// TMTestReduction is the training max multiplier for a lift that fails the
+// 7th-week TM test
+const TMTestReduction = 0.9
+
+// HasTMTest reports whether the config's program includes a 7th-week TM test
+func (c *Config) HasTMTest() bool {
+	if c.Block != nil {
+		return c.Block.SeventhWeek == SeventhWeekTMTest
+	}
+	return c.SeventhWeek == SeventhWeekTMTest
+}
+
+// TMTestConfig returns the config a 7th-week TM test in the program runs on:
+// the config itself for a single cycle, or the config after the leader
+// cycles of a block
+func (c *Config) TMTestConfig() *Config {
+	tested := c.Clone()
+	if tested.Block != nil {
+		for i := 0; i < tested.Block.LeaderCycles; i++ {
+			tested.AdvanceCycle()
+		}
+	}
+	return tested
+}
+
+// Block describes a Forever-style block: leader cycles, an optional 7th
+// week, then anchor cycles, with TM increases applied between cycles
+type Block struct {
+	LeaderTemplate string
+	LeaderCycles   int
+	SeventhWeek    SeventhWeek
+	AnchorTemplate string
+	AnchorCycles   int
+}
+
+// Supplemental is the sets x reps prescription for First/Second Set Last work
+type Supplemental struct {
+	Sets int
+	Reps int
+}
+
 // DefaultLiftOrder is the standard 5/3/1 day order
 var DefaultLiftOrder = []Lift{Squat, Bench, Deadlift, OHP}
 
@@ -18,33 +245,123 @@ func AllLifts() []Lift {
 	return []Lift{Squat, Bench, Deadlift, OHP}
 }
 
-// AccessoryPresets maps each main lift to appropriate accessory options
-var AccessoryPresets = map[Lift][]string{
-	Squat:    {"Leg Curl", "Lunges", "Leg Press", "Bulgarian Split Squat"},
-	Bench:    {"Dumbbell Press", "Dumbbell Row", "Dips", "Tricep Pushdown", "Cable Fly"},
-	Deadlift: {"Barbell Row", "Good Morning", "Hanging Leg Raise", "Back Extension"},
-	OHP:      {"Lateral Raise", "Face Pull", "Rear Delt Fly", "Pull-up"},
+// ParseLift resolves a lift from its full name or a common abbreviation
+func ParseLift(name string) (Lift, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "squat":
+		return Squat, nil
+	case "bench", "bench press":
+		return Bench, nil
+	case "deadlift", "dl":
+		return Deadlift, nil
+	case "ohp", "press", "overhead press":
+		return OHP, nil
+	}
+	return "", fmt.Errorf("unknown lift %q", name)
+}
+
+// OppositeLift returns the standard opposite pairing (Squat/Deadlift, Bench/OHP)
+func OppositeLift(lift Lift) Lift {
+	switch lift {
+	case Squat:
+		return Deadlift
+	case Deadlift:
+		return Squat
+	case Bench:
+		return OHP
+	case OHP:
+		return Bench
+	}
+	return lift
 }
 
 // LiftMaxes holds the training max for each lift
 type LiftMaxes map[Lift]float64
 
-// Config holds all configuration for generating a 5/3/1 BBB program
+// Config holds all configuration for generating a 5/3/1 program
 type Config struct {
+	// Weight unit for training maxes and generated weights (default lbs)
+	Units Unit
+
+	// How generated weights are rounded
+	Rounding Rounding
+
+	// Optional bar and plates available; when set, every generated weight
+	// is rounded (per Rounding.Mode) to one that can actually be loaded
+	Plates *plates.Inventory
+
+	// Program template name (e.g. "bbb"); see program.Templates
+	Template string
+
+	// Main work style (default 5/3/1 with AMRAP top sets)
+	MainWork MainWorkStyle
+
+	// Optional Joker sets after the top set
+	Jokers Jokers
+
+	// Additional main lifts beyond the standard four
+	CustomLifts []CustomLift
+
 	// Training maxes for each lift (already calculated if input was true 1RM)
 	TrainingMaxes LiftMaxes
 
 	// Order of lifts for each training day (Day 1 = index 0, etc.)
 	LiftOrder []Lift
 
+	// Working weeks per cycle, from the start of the 5s/3s/1s wave (default 3)
+	CycleWeeks int
+
+	// When cycles end with a deload week
+	Deload DeloadPolicy
+
+	// Number of the current cycle, starting at 1; advanced with each cycle
+	Cycle int
+
+	// Training days per week: 4 (one main lift per day), 3 (one lift per
+	// day, rolling over into the next week) or 2 (two main lifts per day)
+	DaysPerWeek int
+
 	// BBB percentage (default 50)
 	BBBPercentage float64
 
+	// Optional per-week BBB percentages (index 0 = week 1); weeks without
+	// an entry use BBBPercentage
+	BBBWeekPercentages []float64
+
+	// Optional multi-cycle BBB challenge; overrides the percentages above
+	BBBChallenge *BBBChallenge
+
 	// BBB lift pairing - maps main lift to its BBB lift (same or opposite)
 	BBBPairing map[Lift]Lift
 
-	// Selected accessory for each main lift day
+	// Sets x reps for FSL/SSL supplemental work (default 5x5)
+	Supplemental Supplemental
+
+	// Optional 7th-week protocol that replaces the deload week of a single
+	// cycle (blocks use Block.SeventhWeek instead)
+	SeventhWeek SeventhWeek
+
+	// Optional leader/anchor block; when set, the program spans every
+	// cycle of the block instead of a single cycle
+	Block *Block
+
+	// Selected accessory for each main lift day, done for 5x10
 	Accessories map[Lift]string
+
+	// Assistance work for each main lift day; a lift listed here uses it
+	// instead of its Accessories entry
+	Assistance map[Lift][]Assistance
+
+	// Warm-up sets before each lift's main work; lifts not listed use
+	// DefaultWarmup, and an empty list means no warm-ups
+	Warmups map[Lift][]WarmupSet
+
+	// WarmupOnDeload also warms up before deload week sets, which are
+	// skipped by default
+	WarmupOnDeload bool
+
+	// Optional jumps, throws and conditioning for each main lift day
+	Conditioning map[Lift][]Conditioning
 }
 
 // NewDefaultConfig creates a config with sensible defaults
@@ -58,20 +375,249 @@ func NewDefaultConfig() *Config {
 	}
 
 	return &Config{
+		Template:      DefaultTemplate,
+		MainWork:      MainWork531,
+		Units:         Pounds,
+		Rounding:      Rounding{Mode: RoundNearest},
 		TrainingMaxes: make(LiftMaxes),
 		LiftOrder:     liftOrder,
+		CycleWeeks:    3,
+		Cycle:         1,
+		DaysPerWeek:   4,
 		BBBPercentage: 50.0,
 		BBBPairing:    bbbPairing,
+		Supplemental:  Supplemental{Sets: 5, Reps: 5},
 		Accessories:   make(map[Lift]string),
 	}
 }
 
+// BBBPercentageForWeek returns the BBB percentage for a week of the cycle,
+// honoring an active challenge and per-week percentages
+func (c *Config) BBBPercentageForWeek(week int) float64 {
+	if c.BBBChallenge != nil {
+		return c.BBBChallenge.Current()
+	}
+	if week >= 1 && week <= len(c.BBBWeekPercentages) {
+		return c.BBBWeekPercentages[week-1]
+	}
+	return c.BBBPercentage
+}
+
+// ApplyDefaults fills in any unset fields with the same defaults as NewDefaultConfig
+func (c *Config) ApplyDefaults() {
+	defaults := NewDefaultConfig()
+
+	if c.Template == "" {
+		c.Template = defaults.Template
+	}
+	if c.MainWork == "" {
+		c.MainWork = defaults.MainWork
+	}
+	if c.Jokers.Sets > 0 && c.Jokers.Step == 0 {
+		c.Jokers.Step = 5
+	}
+	if c.Units == "" {
+		c.Units = defaults.Units
+	}
+	if c.Rounding.Mode == "" {
+		c.Rounding.Mode = defaults.Rounding.Mode
+	}
+	if c.TrainingMaxes == nil {
+		c.TrainingMaxes = defaults.TrainingMaxes
+	}
+	if len(c.LiftOrder) == 0 {
+		c.LiftOrder = defaults.LiftOrder
+	}
+	if c.CycleWeeks == 0 {
+		c.CycleWeeks = defaults.CycleWeeks
+	}
+	if c.Cycle == 0 {
+		c.Cycle = defaults.Cycle
+	}
+	if c.DaysPerWeek == 0 {
+		c.DaysPerWeek = defaults.DaysPerWeek
+	}
+	if c.BBBPercentage == 0 {
+		c.BBBPercentage = defaults.BBBPercentage
+	}
+	if c.BBBPairing == nil {
+		c.BBBPairing = make(map[Lift]Lift)
+	}
+	for _, lift := range c.LiftOrder {
+		if _, ok := c.BBBPairing[lift]; !ok {
+			c.BBBPairing[lift] = lift
+		}
+	}
+	if c.Supplemental.Sets == 0 {
+		c.Supplemental.Sets = defaults.Supplemental.Sets
+	}
+	if c.Supplemental.Reps == 0 {
+		c.Supplemental.Reps = defaults.Supplemental.Reps
+	}
+	if c.Accessories == nil {
+		c.Accessories = defaults.Accessories
+	}
+}
+
+// Clone creates a deep copy of the config
+func (c *Config) Clone() *Config {
+	if c == nil {
+		return nil
+	}
+
+	cloned := &Config{
+		Template:       c.Template,
+		MainWork:       c.MainWork,
+		Jokers:         c.Jokers,
+		Units:          c.Units,
+		Rounding:       c.Rounding,
+		TrainingMaxes:  make(LiftMaxes, len(c.TrainingMaxes)),
+		LiftOrder:      append([]Lift{}, c.LiftOrder...),
+		CycleWeeks:     c.CycleWeeks,
+		Deload:         c.Deload,
+		Cycle:          c.Cycle,
+		DaysPerWeek:    c.DaysPerWeek,
+		BBBPercentage:  c.BBBPercentage,
+		BBBPairing:     make(map[Lift]Lift, len(c.BBBPairing)),
+		Supplemental:   c.Supplemental,
+		SeventhWeek:    c.SeventhWeek,
+		Accessories:    make(map[Lift]string, len(c.Accessories)),
+		WarmupOnDeload: c.WarmupOnDeload,
+	}
+
+	if c.BBBWeekPercentages != nil {
+		cloned.BBBWeekPercentages = append([]float64{}, c.BBBWeekPercentages...)
+	}
+	if c.BBBChallenge != nil {
+		cloned.BBBChallenge = &BBBChallenge{
+			Percentages: append([]float64{}, c.BBBChallenge.Percentages...),
+			Month:       c.BBBChallenge.Month,
+		}
+	}
+	if c.Block != nil {
+		block := *c.Block
+		cloned.Block = &block
+	}
+	for _, custom := range c.CustomLifts {
+		custom.Aliases = append([]string(nil), custom.Aliases...)
+		custom.Accessories = append([]string(nil), custom.Accessories...)
+		custom.HevyNames = append([]string(nil), custom.HevyNames...)
+		cloned.CustomLifts = append(cloned.CustomLifts, custom)
+	}
+	if c.Plates != nil {
+		inventory := *c.Plates
+		inventory.Plates = append([]plates.Plate{}, c.Plates.Plates...)
+		cloned.Plates = &inventory
+	}
+
+	for lift, max := range c.TrainingMaxes {
+		cloned.TrainingMaxes[lift] = max
+	}
+	for lift, paired := range c.BBBPairing {
+		cloned.BBBPairing[lift] = paired
+	}
+	for lift, accessory := range c.Accessories {
+		cloned.Accessories[lift] = accessory
+	}
+	if c.Assistance != nil {
+		cloned.Assistance = make(map[Lift][]Assistance, len(c.Assistance))
+		for lift, work := range c.Assistance {
+			copied := make([]Assistance, len(work))
+			for i, a := range work {
+				if a.TotalReps != nil {
+					target := *a.TotalReps
+					a.TotalReps = &target
+				}
+				copied[i] = a
+			}
+			cloned.Assistance[lift] = copied
+		}
+	}
+	if c.Warmups != nil {
+		cloned.Warmups = make(map[Lift][]WarmupSet, len(c.Warmups))
+		for lift, sets := range c.Warmups {
+			cloned.Warmups[lift] = append([]WarmupSet{}, sets...)
+		}
+	}
+	if c.Conditioning != nil {
+		cloned.Conditioning = make(map[Lift][]Conditioning, len(c.Conditioning))
+		for lift, work := range c.Conditioning {
+			cloned.Conditioning[lift] = append([]Conditioning{}, work...)
+		}
+	}
+
+	return cloned
+}
+
+// AdvanceCycle moves the config on by one cycle: the cycle number goes up,
+// standard TM increases are applied to every lift with a training max, and
+// an active BBB challenge moves to its next month (ending after the last one).
+func (c *Config) AdvanceCycle() {
+	c.Cycle++
+	for _, lift := range c.Lifts() {
+		if c.TrainingMaxes[lift] > 0 {
+			c.TrainingMaxes[lift] += c.CycleIncrement(lift)
+		}
+	}
+
+	if challenge := c.BBBChallenge; challenge != nil {
+		challenge.Month++
+		if challenge.Month > len(challenge.Percentages) {
+			c.BBBChallenge = nil
+		}
+	}
+}
+
+// HasDeload reports whether the current cycle ends with a deload week under
+// the config's deload policy. inBlock is set for leader and anchor cycles.
+func (c *Config) HasDeload(inBlock bool) bool {
+	switch c.Deload.Mode {
+	case DeloadAlways:
+		return true
+	case DeloadNever:
+		return false
+	case DeloadEvery:
+		return c.Deload.Every > 0 && c.Cycle%c.Deload.Every == 0
+	default:
+		return !inBlock
+	}
+}
+
 // CalculateTrainingMax returns 90% of the true 1RM (unrounded for precision)
 func CalculateTrainingMax(true1RM float64) float64 {
 	return true1RM * 0.9
 }
 
-// RoundToNearest5 rounds a weight to the nearest 5 lbs
+// EstimateTrainingMax returns the training max for a recent weight x reps
+// result: 90% of its estimated 1RM under the given formula
+func EstimateTrainingMax(result e1rm.Result, formula e1rm.Formula) (float64, error) {
+	estimate, err := formula.Estimate(result)
+	if err != nil {
+		return 0, err
+	}
+	return CalculateTrainingMax(estimate), nil
+}
+
+// RoundWeight applies the config's rounding policy to a weight. With a plate
+// inventory the result is the closest loadable weight in the rounding direction.
+func (c *Config) RoundWeight(weight float64) float64 {
+	if c.Plates == nil {
+		return c.Rounding.Round(weight, c.Units)
+	}
+
+	switch c.Rounding.Mode {
+	case RoundDown:
+		return c.Plates.Floor(weight)
+	case RoundUp:
+		return c.Plates.Ceil(weight)
+	default:
+		return c.Plates.Nearest(weight)
+	}
+}
+
+// RoundToNearest5 rounds a weight to the nearest 5 lbs.
+//
+// Deprecated: use Config.RoundWeight, which honors units and rounding policy.
 func RoundToNearest5(weight float64) float64 {
 	return float64(int((weight+2.5)/5) * 5)
 }
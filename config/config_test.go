@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestMain registers the program package's template names, since config
+// tests can't import program
+func TestMain(m *testing.M) {
+	for _, name := range []string{"bbb", "fsl", "ssl"} {
+		RegisterTemplate(name)
+	}
+	os.Exit(m.Run())
+}
+
+// fullConfig loads testdata/full_config.json, which sets every Config field
+// a valid config can combine, and then sets the rest: a single-cycle
+// SeventhWeek (not used with a block) and per-week BBB percentages (not used
+// with a BBB challenge)
+func fullConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := LoadFile("testdata/full_config.json")
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	cfg.SeventhWeek = SeventhWeekTMTest
+	cfg.BBBWeekPercentages = []float64{50, 60, 70}
+	return cfg
+}
+
+// TestCloneKeepsEveryField checks Clone copies every Config field, and
+// copies rather than shares its slices, maps and pointers
+func TestCloneKeepsEveryField(t *testing.T) {
+	cfg := fullConfig(t)
+	value := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if value.Field(i).IsZero() {
+			t.Fatalf("testdata/full_config.json leaves %s unset; set it so Clone is tested", value.Type().Field(i).Name)
+		}
+	}
+
+	cloned := cfg.Clone()
+	if !reflect.DeepEqual(cloned, cfg) {
+		t.Errorf("Clone = %+v, want %+v", cloned, cfg)
+	}
+
+	cloned.Plates.Plates[0].Pairs = 1
+	cloned.CustomLifts[0].Aliases[0] = "changed"
+	cloned.TrainingMaxes[Squat] = 1
+	cloned.BBBChallenge.Percentages[0] = 1
+	cloned.Block.LeaderCycles = 1
+	cloned.Assistance[Bench][1].TotalReps.Min = 1
+	cloned.Warmups[Deadlift][0].Reps = 1
+	cloned.Conditioning[Deadlift][0].Sets = 1
+	if !reflect.DeepEqual(cfg, fullConfig(t)) {
+		t.Error("changing the clone changed the original")
+	}
+}
+
+// TestCloneKeepsWarmupOnDeload is a regression test for Clone dropping the
+// deload warm-up setting
+func TestCloneKeepsWarmupOnDeload(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.WarmupOnDeload = true
+	if !cfg.Clone().WarmupOnDeload {
+		t.Error("Clone dropped WarmupOnDeload")
+	}
+}
+
+// TestValidateTemplates checks Template and the block templates must name a
+// registered template
+func TestValidateTemplates(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(cfg *Config)
+		field string
+	}{
+		{name: "template", edit: func(cfg *Config) { cfg.Template = "bbbb" }, field: "Template"},
+		{name: "leader", edit: func(cfg *Config) { cfg.Block.LeaderTemplate = "5x5" }, field: "Block.LeaderTemplate"},
+		{name: "anchor", edit: func(cfg *Config) { cfg.Block.AnchorTemplate = "pr" }, field: "Block.AnchorTemplate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadFile("testdata/full_config.json")
+			if err != nil {
+				t.Fatalf("LoadFile: %v", err)
+			}
+			tt.edit(cfg)
+			err = cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.field+": unknown template") {
+				t.Fatalf("Validate = %v, want an unknown template error for %s", err, tt.field)
+			}
+		})
+	}
+
+	cfg, err := LoadFile("testdata/full_config.json")
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	cfg.Template = "FSL"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate with an upper-case template = %v, want nil", err)
+	}
+}
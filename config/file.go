@@ -0,0 +1,394 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"lifting/e1rm"
+)
+
+// FileVersion is the current version of the config file format
+const FileVersion = 1
+
+// fileBody is the on-disk layout of a config file.
+//
+// Config fields sit at the top level next to "version", using the same keys
+// memory snapshots use. A memory snapshot ({"saved_at": ..., "config": {...}})
+// is also accepted as-is, so saved memory can be checked in as a config file.
+//
+// "estimates" gives recent weight x reps results (e.g. {"squat": "225x8"})
+// to set training maxes from, using "e1rm_formula" (default wendler).
+type fileBody struct {
+	Version   int               `json:"version,omitempty"`
+	SavedAt   json.RawMessage   `json:"saved_at,omitempty"`
+	History   json.RawMessage   `json:"history,omitempty"` // memory snapshot history, ignored
+	Estimates map[string]string `json:"estimates,omitempty"`
+	Formula   string            `json:"e1rm_formula,omitempty"`
+	Nested    *Config           `json:"config,omitempty"`
+	*Config
+}
+
+// FileError points at the field and line of a config file that failed to load
+type FileError struct {
+	File  string
+	Line  int
+	Field string
+	Err   error
+}
+
+func (e *FileError) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", e.File, e.Line)
+	}
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %v", location, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", location, e.Field, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// LoadFile reads a JSON config file, or a YAML one with the same keys when
+// path ends in .yaml or .yml. Lift names may use the same short forms as
+// ParseLift (e.g. "bench", "ohp"), and omitted fields take their defaults.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, &FileError{File: path, Err: err}
+		}
+	}
+	return parseFile(path, data)
+}
+
+// WriteFile writes the config to path in the current config file format
+func WriteFile(path string, cfg *Config) error {
+	if cfg == nil {
+		return fmt.Errorf("cannot write nil config")
+	}
+
+	data, err := json.MarshalIndent(fileBody{Version: FileVersion, Config: cfg}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+// parseFile decodes and normalizes config file contents
+func parseFile(path string, data []byte) (*Config, error) {
+	lines := fieldLines(data)
+	fail := func(field string, err error) *FileError {
+		return &FileError{File: path, Line: lines[field], Field: field, Err: err}
+	}
+
+	var body fileBody
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&body); err != nil {
+		return nil, decodeError(path, data, lines, err)
+	}
+
+	// A memory snapshot's version is its own schema version
+	if body.SavedAt == nil && body.Version > FileVersion {
+		return nil, fail("version", fmt.Errorf("unsupported version %d (latest is %d)", body.Version, FileVersion))
+	}
+
+	cfg, prefix := body.Config, ""
+	if body.Nested != nil {
+		if body.Config != nil {
+			return nil, fail("config", fmt.Errorf("config fields must be either nested under \"config\" or at the top level, not both"))
+		}
+		cfg, prefix = body.Nested, "config."
+	}
+	if cfg == nil {
+		return nil, &FileError{File: path, Err: fmt.Errorf("file contains no configuration")}
+	}
+
+	rawFields := make(map[string]string)
+	errs := canonicalizeLifts(cfg, prefix, rawFields, fail)
+	if cfg.Units != "" {
+		unit, err := ParseUnit(string(cfg.Units))
+		if err != nil {
+			errs = append(errs, fail(prefix+"Units", err))
+		}
+		cfg.Units = unit
+	}
+	errs = append(errs, applyEstimates(cfg, body.Estimates, body.Formula, fail)...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	cfg.ApplyDefaults()
+
+	// Point validation errors back at the fields as written in the file
+	var invalid ValidationErrors
+	if errors.As(cfg.Validate(), &invalid) {
+		errs := make([]error, len(invalid))
+		for i, fieldErr := range invalid {
+			field, ok := rawFields[fieldErr.Field]
+			if !ok {
+				field = prefix + fieldErr.Field
+			}
+			errs[i] = &FileError{File: path, Line: lineOf(lines, field), Field: field, Err: errors.New(fieldErr.Msg)}
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	return cfg, nil
+}
+
+// applyEstimates sets the training max of each lift in estimates from its
+// weight x reps result. A lift can't have both an estimate and a training max.
+func applyEstimates(cfg *Config, estimates map[string]string, formulaName string, fail func(string, error) *FileError) []error {
+	if len(estimates) == 0 && formulaName == "" {
+		return nil
+	}
+	formula, err := e1rm.ParseFormula(formulaName)
+	if err != nil {
+		return []error{fail("e1rm_formula", err)}
+	}
+
+	var errs []error
+	for name, value := range estimates {
+		field := "estimates." + name
+		lift, err := cfg.ParseLift(name)
+		if err != nil {
+			errs = append(errs, fail(field, err))
+			continue
+		}
+		if _, exists := cfg.TrainingMaxes[lift]; exists {
+			errs = append(errs, fail(field, fmt.Errorf("%s also has a training max; set one or the other", lift)))
+			continue
+		}
+		result, err := e1rm.ParseResult(value)
+		if err == nil {
+			if cfg.TrainingMaxes == nil {
+				cfg.TrainingMaxes = make(LiftMaxes)
+			}
+			cfg.TrainingMaxes[lift], err = EstimateTrainingMax(result, formula)
+		}
+		if err != nil {
+			errs = append(errs, fail(field, err))
+		}
+	}
+	return errs
+}
+
+// canonicalizeLifts rewrites lift names to their canonical form, reporting
+// every name that does not resolve to a standard or custom lift. rawFields
+// records the path as written in the file for each canonical field path.
+func canonicalizeLifts(cfg *Config, prefix string, rawFields map[string]string, fail func(string, error) *FileError) []error {
+	var errs []error
+	resolve := func(field, name string) Lift {
+		lift, err := cfg.ParseLift(name)
+		if err != nil {
+			errs = append(errs, fail(field, err))
+		}
+		return lift
+	}
+	record := func(group string, lift Lift, raw string) {
+		rawFields[group+"."+string(lift)] = prefix + group + "." + raw
+	}
+
+	for i, custom := range cfg.CustomLifts {
+		if custom.Opposite != "" {
+			cfg.CustomLifts[i].Opposite = resolve(fmt.Sprintf("%sCustomLifts[%d].Opposite", prefix, i), string(custom.Opposite))
+		}
+	}
+
+	if cfg.TrainingMaxes != nil {
+		maxes := make(LiftMaxes, len(cfg.TrainingMaxes))
+		for name, max := range cfg.TrainingMaxes {
+			lift := resolve(prefix+"TrainingMaxes."+string(name), string(name))
+			record("TrainingMaxes", lift, string(name))
+			maxes[lift] = max
+		}
+		cfg.TrainingMaxes = maxes
+	}
+
+	for i, name := range cfg.LiftOrder {
+		cfg.LiftOrder[i] = resolve(fmt.Sprintf("%sLiftOrder[%d]", prefix, i), string(name))
+	}
+
+	if cfg.BBBPairing != nil {
+		pairing := make(map[Lift]Lift, len(cfg.BBBPairing))
+		for name, paired := range cfg.BBBPairing {
+			field := prefix + "BBBPairing." + string(name)
+			lift := resolve(field, string(name))
+			record("BBBPairing", lift, string(name))
+			pairing[lift] = resolve(field, string(paired))
+		}
+		cfg.BBBPairing = pairing
+	}
+
+	if cfg.Assistance != nil {
+		assistance := make(map[Lift][]Assistance, len(cfg.Assistance))
+		for name, work := range cfg.Assistance {
+			lift := resolve(prefix+"Assistance."+string(name), string(name))
+			record("Assistance", lift, string(name))
+			assistance[lift] = work
+		}
+		cfg.Assistance = assistance
+	}
+
+	if cfg.Warmups != nil {
+		warmups := make(map[Lift][]WarmupSet, len(cfg.Warmups))
+		for name, sets := range cfg.Warmups {
+			lift := resolve(prefix+"Warmups."+string(name), string(name))
+			record("Warmups", lift, string(name))
+			warmups[lift] = sets
+		}
+		cfg.Warmups = warmups
+	}
+
+	if cfg.Conditioning != nil {
+		conditioning := make(map[Lift][]Conditioning, len(cfg.Conditioning))
+		for name, work := range cfg.Conditioning {
+			lift := resolve(prefix+"Conditioning."+string(name), string(name))
+			record("Conditioning", lift, string(name))
+			conditioning[lift] = work
+		}
+		cfg.Conditioning = conditioning
+	}
+
+	if cfg.Accessories != nil {
+		accessories := make(map[Lift]string, len(cfg.Accessories))
+		for name, accessory := range cfg.Accessories {
+			lift := resolve(prefix+"Accessories."+string(name), string(name))
+			record("Accessories", lift, string(name))
+			accessories[lift] = accessory
+		}
+		cfg.Accessories = accessories
+	}
+
+	return errs
+}
+
+// lineOf returns the line of a field, falling back to its nearest parent
+// for fields that are missing from the file
+func lineOf(lines map[string]int, field string) int {
+	for field != "" {
+		if line, ok := lines[field]; ok {
+			return line
+		}
+		cut := strings.LastIndexAny(field, ".[")
+		if cut < 0 {
+			break
+		}
+		field = field[:cut]
+	}
+	return 0
+}
+
+// decodeError converts a JSON decoding error into a FileError with a line number
+func decodeError(path string, data []byte, lines map[string]int, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &FileError{File: path, Line: lineAt(data, syntaxErr.Offset), Err: err}
+	case errors.As(err, &typeErr):
+		return &FileError{
+			File:  path,
+			Line:  lineAt(data, typeErr.Offset),
+			Field: typeErr.Field,
+			Err:   fmt.Errorf("expected %s, got %s", typeErr.Type, typeErr.Value),
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field, _ := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+		line := lines[field]
+		if line == 0 {
+			line = lines["config."+field]
+		}
+		return &FileError{File: path, Line: line, Field: field, Err: fmt.Errorf("unknown field")}
+	}
+
+	return &FileError{File: path, Err: err}
+}
+
+// fieldLines maps each dotted JSON path in data (e.g. "TrainingMaxes.bench",
+// "LiftOrder[2]") to the line it appears on
+func fieldLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	type frame struct {
+		path    string
+		object  bool
+		key     string
+		wantKey bool
+		index   int
+	}
+	var stack []*frame
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return lines
+		}
+		line := lineAt(data, dec.InputOffset())
+
+		// Closing delimiters end the current container
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		path := ""
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			switch {
+			case top.object && top.wantKey:
+				top.key, _ = tok.(string)
+				top.wantKey = false
+				lines[joinPath(top.path, top.key)] = line
+				continue
+			case top.object:
+				path = joinPath(top.path, top.key)
+				top.wantKey = true
+			default:
+				path = fmt.Sprintf("%s[%d]", top.path, top.index)
+				lines[path] = line
+				top.index++
+			}
+		}
+
+		if d, ok := tok.(json.Delim); ok {
+			stack = append(stack, &frame{path: path, object: d == '{', wantKey: d == '{'})
+		}
+	}
+}
+
+// joinPath appends a key to a dotted JSON path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// lineAt returns the 1-based line number of a byte offset in data
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
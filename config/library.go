@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultLibraryFile is the exercise library file loaded when present
+const DefaultLibraryFile = "531_exercises.json"
+
+// Exercise is one entry of the exercise library
+type Exercise struct {
+	Name         string
+	Category     AssistanceCategory // assistance category, empty for main lifts
+	Conditioning ConditioningKind   // set for jumps, throws and conditioning exercises
+	Lifts        []Lift             // main lift days the exercise is an accessory option for
+	Sets         int                // default prescription
+	Reps         int
+	Equipment    string   // e.g. "barbell", "dumbbell", "cable", "machine", "bodyweight"
+	HevyAliases  []string // Hevy exercise titles to try when the name has no exact match
+}
+
+// Library is the set of exercises the prompts and Hevy mapper know about
+type Library struct {
+	Exercises []Exercise
+}
+
+// DefaultLibrary returns the built-in exercise library
+func DefaultLibrary() *Library {
+	return &Library{Exercises: []Exercise{
+		// Main lifts
+		{Name: "Squat", Equipment: "barbell", HevyAliases: []string{"barbell squat", "squat (barbell)"}},
+		{Name: "Bench Press", Equipment: "barbell", HevyAliases: []string{"barbell bench press", "bench press (barbell)"}},
+		{Name: "Deadlift", Equipment: "barbell", HevyAliases: []string{"barbell deadlift", "deadlift (barbell)"}},
+		{Name: "Overhead Press", Equipment: "barbell", HevyAliases: []string{"overhead press (barbell)", "barbell overhead press", "shoulder press (barbell)"}},
+
+		// Squat day
+		{Name: "Leg Curl", Category: AssistanceSingleLegCore, Lifts: []Lift{Squat}, Sets: 5, Reps: 10, Equipment: "machine", HevyAliases: []string{"lying leg curl", "leg curl (machine)", "seated leg curl"}},
+		{Name: "Lunges", Category: AssistanceSingleLegCore, Lifts: []Lift{Squat}, Sets: 5, Reps: 10, Equipment: "dumbbell", HevyAliases: []string{"lunge (dumbbell)", "walking lunge", "lunge (barbell)"}},
+		{Name: "Leg Press", Category: AssistanceSingleLegCore, Lifts: []Lift{Squat}, Sets: 5, Reps: 10, Equipment: "machine", HevyAliases: []string{"leg press (machine)", "leg press"}},
+		{Name: "Bulgarian Split Squat", Category: AssistanceSingleLegCore, Lifts: []Lift{Squat}, Sets: 5, Reps: 10, Equipment: "dumbbell", HevyAliases: []string{"bulgarian split squat", "split squat"}},
+
+		// Bench day
+		{Name: "Dumbbell Press", Category: AssistancePush, Lifts: []Lift{Bench}, Sets: 5, Reps: 10, Equipment: "dumbbell", HevyAliases: []string{"dumbbell bench press", "bench press (dumbbell)", "dumbbell chest press"}},
+		{Name: "Dumbbell Row", Category: AssistancePull, Lifts: []Lift{Bench}, Sets: 5, Reps: 10, Equipment: "dumbbell", HevyAliases: []string{"dumbbell row", "bent over row (dumbbell)", "one arm dumbbell row"}},
+		{Name: "Dips", Category: AssistancePush, Lifts: []Lift{Bench}, Sets: 5, Reps: 10, Equipment: "bodyweight", HevyAliases: []string{"dip", "tricep dip", "chest dip"}},
+		{Name: "Tricep Pushdown", Category: AssistancePush, Lifts: []Lift{Bench}, Sets: 5, Reps: 10, Equipment: "cable", HevyAliases: []string{"tricep pushdown", "triceps pushdown", "cable pushdown"}},
+		{Name: "Cable Fly", Category: AssistancePush, Lifts: []Lift{Bench}, Sets: 5, Reps: 10, Equipment: "cable", HevyAliases: []string{"cable fly", "cable chest fly", "cable crossover"}},
+
+		// Deadlift day
+		{Name: "Barbell Row", Category: AssistancePull, Lifts: []Lift{Deadlift}, Sets: 5, Reps: 10, Equipment: "barbell", HevyAliases: []string{"bent over row (barbell)", "barbell bent over row", "bent over row"}},
+		{Name: "Good Morning", Category: AssistanceSingleLegCore, Lifts: []Lift{Deadlift}, Sets: 5, Reps: 10, Equipment: "barbell", HevyAliases: []string{"good morning", "good morning (barbell)"}},
+		{Name: "Hanging Leg Raise", Category: AssistanceSingleLegCore, Lifts: []Lift{Deadlift}, Sets: 5, Reps: 10, Equipment: "bodyweight", HevyAliases: []string{"hanging leg raise", "hanging knee raise"}},
+		{Name: "Back Extension", Category: AssistanceSingleLegCore, Lifts: []Lift{Deadlift}, Sets: 5, Reps: 10, Equipment: "bodyweight", HevyAliases: []string{"back extension", "hyperextension", "back extension (machine)"}},
+
+		// OHP day
+		{Name: "Lateral Raise", Category: AssistancePush, Lifts: []Lift{OHP}, Sets: 5, Reps: 10, Equipment: "dumbbell", HevyAliases: []string{"lateral raise (dumbbell)", "dumbbell lateral raise", "lateral raise"}},
+		{Name: "Face Pull", Category: AssistancePull, Lifts: []Lift{OHP}, Sets: 5, Reps: 10, Equipment: "cable", HevyAliases: []string{"face pull", "face pull (cable)"}},
+		{Name: "Rear Delt Fly", Category: AssistancePull, Lifts: []Lift{OHP}, Sets: 5, Reps: 10, Equipment: "dumbbell", HevyAliases: []string{"reverse fly (dumbbell)", "rear delt fly", "reverse fly"}},
+		{Name: "Pull-up", Category: AssistancePull, Lifts: []Lift{OHP}, Sets: 5, Reps: 10, Equipment: "bodyweight", HevyAliases: []string{"pull up", "pull-up", "pullup"}},
+
+		// Other assistance
+		{Name: "Push-up", Category: AssistancePush, Sets: 5, Reps: 10, Equipment: "bodyweight", HevyAliases: []string{"push up", "push-up", "pushup"}},
+		{Name: "Chin-up", Category: AssistancePull, Sets: 5, Reps: 10, Equipment: "bodyweight", HevyAliases: []string{"chin up", "chin-up", "chinup"}},
+		{Name: "Ab Wheel", Category: AssistanceSingleLegCore, Sets: 5, Reps: 10, Equipment: "bodyweight", HevyAliases: []string{"ab wheel", "ab wheel rollout", "ab rollout"}},
+
+		// Jumps and throws
+		{Name: "Box Jump", Conditioning: ConditioningJumps, Sets: 3, Reps: 5, Equipment: "box", HevyAliases: []string{"box jump"}},
+		{Name: "Broad Jump", Conditioning: ConditioningJumps, Sets: 3, Reps: 5, Equipment: "bodyweight", HevyAliases: []string{"broad jump", "standing broad jump"}},
+		{Name: "Medicine Ball Throw", Conditioning: ConditioningThrows, Sets: 3, Reps: 5, Equipment: "medicine ball", HevyAliases: []string{"medicine ball chest pass", "medicine ball throw", "chest pass"}},
+		{Name: "Medicine Ball Slam", Conditioning: ConditioningThrows, Sets: 3, Reps: 5, Equipment: "medicine ball", HevyAliases: []string{"medicine ball slam", "ball slams"}},
+
+		// Conditioning
+		{Name: "Hill Sprints", Conditioning: ConditioningCardio, Sets: 10, Equipment: "none", HevyAliases: []string{"sprints", "running"}},
+		{Name: "Prowler Push", Conditioning: ConditioningCardio, Sets: 6, Equipment: "sled", HevyAliases: []string{"sled push", "prowler push"}},
+		{Name: "Sled Drag", Conditioning: ConditioningCardio, Sets: 6, Equipment: "sled", HevyAliases: []string{"sled drag", "sled pull"}},
+		{Name: "Kettlebell Swing", Conditioning: ConditioningCardio, Sets: 5, Reps: 20, Equipment: "kettlebell", HevyAliases: []string{"kettlebell swing"}},
+		{Name: "Walking", Conditioning: ConditioningCardio, Sets: 1, Equipment: "none", HevyAliases: []string{"walking", "walk"}},
+	}}
+}
+
+// activeLibrary is the library used by validation, the prompts and Hevy
+var activeLibrary = DefaultLibrary()
+
+// ActiveLibrary returns the exercise library in use
+func ActiveLibrary() *Library {
+	return activeLibrary
+}
+
+// UseLibrary replaces the exercise library in use
+func UseLibrary(lib *Library) {
+	activeLibrary = lib
+}
+
+// Lookup returns the exercise with the given name (case-insensitive)
+func (l *Library) Lookup(name string) (Exercise, bool) {
+	for _, ex := range l.Exercises {
+		if strings.EqualFold(ex.Name, strings.TrimSpace(name)) {
+			return ex, true
+		}
+	}
+	return Exercise{}, false
+}
+
+// ForLift returns the names of the accessory options for a main lift's day
+func (l *Library) ForLift(lift Lift) []string {
+	var names []string
+	for _, ex := range l.Exercises {
+		for _, exLift := range ex.Lifts {
+			if exLift == lift {
+				names = append(names, ex.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// ForConditioning returns the names of the exercises of a conditioning kind
+func (l *Library) ForConditioning(kind ConditioningKind) []string {
+	var names []string
+	for _, ex := range l.Exercises {
+		if ex.Conditioning == kind {
+			names = append(names, ex.Name)
+		}
+	}
+	return names
+}
+
+// InCategory returns the names of the exercises in an assistance category
+func (l *Library) InCategory(category AssistanceCategory) []string {
+	var names []string
+	for _, ex := range l.Exercises {
+		if ex.Category == category {
+			names = append(names, ex.Name)
+		}
+	}
+	return names
+}
+
+// Merge returns a copy of the library with other's exercises added; an
+// exercise with the same name as an existing one replaces it
+func (l *Library) Merge(other *Library) *Library {
+	merged := &Library{Exercises: append([]Exercise{}, l.Exercises...)}
+	for _, ex := range other.Exercises {
+		replaced := false
+		for i := range merged.Exercises {
+			if strings.EqualFold(merged.Exercises[i].Name, ex.Name) {
+				merged.Exercises[i] = ex
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Exercises = append(merged.Exercises, ex)
+		}
+	}
+	return merged
+}
+
+// LoadLibrary reads an exercise library file and merges it over the
+// built-in library. If the file does not exist, it returns (nil, nil).
+func LoadLibrary(path string) (*Library, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read exercise library: %w", err)
+	}
+
+	var lib Library
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&lib); err != nil {
+		return nil, fmt.Errorf("failed to parse exercise library %s: %w", path, err)
+	}
+
+	var errs []error
+	for i, ex := range lib.Exercises {
+		field := fmt.Sprintf("%s: Exercises[%d]", path, i)
+		if strings.TrimSpace(ex.Name) == "" {
+			errs = append(errs, fmt.Errorf("%s.Name: required", field))
+		}
+		switch ex.Category {
+		case "", AssistancePush, AssistancePull, AssistanceSingleLegCore:
+		default:
+			errs = append(errs, fmt.Errorf("%s.Category: must be %q, %q, %q or empty, got %q", field, AssistancePush, AssistancePull, AssistanceSingleLegCore, ex.Category))
+		}
+		switch ex.Conditioning {
+		case "", ConditioningJumps, ConditioningThrows, ConditioningCardio:
+		default:
+			errs = append(errs, fmt.Errorf("%s.Conditioning: must be %q, %q, %q or empty, got %q", field, ConditioningJumps, ConditioningThrows, ConditioningCardio, ex.Conditioning))
+		}
+		for j, name := range ex.Lifts {
+			lift, err := ParseLift(string(name))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s.Lifts[%d]: %w", field, j, err))
+			}
+			lib.Exercises[i].Lifts[j] = lift
+		}
+		if ex.Sets < 0 || ex.Reps < 0 {
+			errs = append(errs, fmt.Errorf("%s: sets and reps must not be negative", field))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return DefaultLibrary().Merge(&lib), nil
+}
+
+// WriteLibrary writes a library as an indented JSON file
+func WriteLibrary(path string, lib *Library) error {
+	data, err := json.MarshalIndent(lib, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode exercise library: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write exercise library: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CustomLift is an additional main lift, such as a front squat or trap-bar
+// deadlift, programmed alongside or instead of the standard four
+type CustomLift struct {
+	Name        Lift     // display name, e.g. "Front Squat"
+	Aliases     []string // other names accepted wherever a lift is named, e.g. "fsq"
+	LowerBody   bool     // uses the lower body training max increase
+	Opposite    Lift     // opposite lift for BBB pairing (default: the lift itself)
+	Accessories []string // accessory presets for the lift's day
+	HevyNames   []string // Hevy exercise titles to try, e.g. "Front Squat (Barbell)"
+}
+
+// Lifts returns the standard lifts followed by the config's custom lifts
+func (c *Config) Lifts() []Lift {
+	lifts := AllLifts()
+	for _, custom := range c.CustomLifts {
+		lifts = append(lifts, custom.Name)
+	}
+	return lifts
+}
+
+// CustomLift returns the custom lift with the given name
+func (c *Config) CustomLift(lift Lift) (CustomLift, bool) {
+	for _, custom := range c.CustomLifts {
+		if custom.Name == lift {
+			return custom, true
+		}
+	}
+	return CustomLift{}, false
+}
+
+// ParseLift resolves a standard lift like the package-level ParseLift, or a
+// custom lift by its name or one of its aliases (case-insensitive)
+func (c *Config) ParseLift(name string) (Lift, error) {
+	if lift, err := ParseLift(name); err == nil {
+		return lift, nil
+	}
+
+	wanted := strings.ToLower(strings.TrimSpace(name))
+	for _, custom := range c.CustomLifts {
+		if strings.ToLower(string(custom.Name)) == wanted {
+			return custom.Name, nil
+		}
+		for _, alias := range custom.Aliases {
+			if strings.ToLower(strings.TrimSpace(alias)) == wanted {
+				return custom.Name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unknown lift %q", name)
+}
+
+// OppositeLift returns the opposite BBB pairing for a lift, using a custom
+// lift's Opposite when set
+func (c *Config) OppositeLift(lift Lift) Lift {
+	if custom, ok := c.CustomLift(lift); ok {
+		if custom.Opposite != "" {
+			return custom.Opposite
+		}
+		return lift
+	}
+	return OppositeLift(lift)
+}
+
+// CycleIncrement returns the standard training max increase for a lift in
+// the config's unit; custom lifts follow their LowerBody setting
+func (c *Config) CycleIncrement(lift Lift) float64 {
+	if custom, ok := c.CustomLift(lift); ok {
+		return cycleIncrement(custom.LowerBody, c.Units)
+	}
+	return CycleIncrement(lift, c.Units)
+}
+
+// AccessoryOptions returns the accessory presets for a lift's day
+func (c *Config) AccessoryOptions(lift Lift) []string {
+	if custom, ok := c.CustomLift(lift); ok {
+		return custom.Accessories
+	}
+	return ActiveLibrary().ForLift(lift)
+}
@@ -0,0 +1,400 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldError describes a single problem with one config field
+type FieldError struct {
+	Field string // e.g. "TrainingMaxes.Squat", "LiftOrder[2]"
+	Msg   string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Msg)
+}
+
+// ValidationErrors lists every problem found by Validate
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fieldErr := range e {
+		msgs[i] = fieldErr.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap exposes each field error to errors.Is and errors.As
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fieldErr := range e {
+		errs[i] = fieldErr
+	}
+	return errs
+}
+
+// Validate checks the config for anything that would produce a broken
+// program. It returns nil or a ValidationErrors listing every problem.
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+	add := func(field, format string, args ...any) {
+		errs = append(errs, &FieldError{Field: field, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	if c.Units != Pounds && c.Units != Kilograms {
+		add("Units", "must be %q or %q, got %q", Pounds, Kilograms, c.Units)
+	}
+
+	// Program templates
+	checkTemplate := func(field, name string) {
+		if !templateNames[strings.ToLower(name)] {
+			add(field, "unknown template %q (available: %s)", name, strings.Join(TemplateNames(), ", "))
+		}
+	}
+	checkTemplate("Template", c.Template)
+	if block := c.Block; block != nil {
+		if block.LeaderTemplate != "" {
+			checkTemplate("Block.LeaderTemplate", block.LeaderTemplate)
+		}
+		if block.AnchorTemplate != "" {
+			checkTemplate("Block.AnchorTemplate", block.AnchorTemplate)
+		}
+	}
+
+	// Main work style and Joker sets
+	switch c.MainWork {
+	case MainWork531, MainWork5sPro:
+	default:
+		add("MainWork", "must be %q or %q, got %q", MainWork531, MainWork5sPro, c.MainWork)
+	}
+	if c.Jokers.Sets < 0 {
+		add("Jokers.Sets", "must not be negative, got %d", c.Jokers.Sets)
+	}
+	if c.Jokers.Sets > 0 {
+		if c.Jokers.Step < 5 || c.Jokers.Step > 10 {
+			add("Jokers.Step", "must be between 5 and 10 percent, got %g", c.Jokers.Step)
+		}
+		if c.MainWork == MainWork5sPro {
+			add("Jokers.Sets", "Joker sets cannot be combined with 5s PRO main work")
+		}
+	}
+
+	// Rounding policy and plate inventory
+	if c.Rounding.Increment < 0 {
+		add("Rounding.Increment", "must not be negative, got %g", c.Rounding.Increment)
+	}
+	switch c.Rounding.Mode {
+	case RoundNearest, RoundDown, RoundUp:
+	default:
+		add("Rounding.Mode", "must be %q, %q or %q, got %q", RoundNearest, RoundDown, RoundUp, c.Rounding.Mode)
+	}
+	if c.Plates != nil {
+		if c.Plates.BarWeight <= 0 {
+			add("Plates.BarWeight", "must be positive, got %g", c.Plates.BarWeight)
+		}
+		if len(c.Plates.Plates) == 0 {
+			add("Plates.Plates", "at least one plate is required")
+		}
+		for i, p := range c.Plates.Plates {
+			field := fmt.Sprintf("Plates.Plates[%d]", i)
+			if p.Weight <= 0 {
+				add(field, "plate weight must be positive, got %g", p.Weight)
+			}
+			if p.Pairs < 0 {
+				add(field, "pair count must not be negative, got %d", p.Pairs)
+			}
+		}
+	}
+
+	known := make(map[Lift]bool)
+	for _, lift := range AllLifts() {
+		known[lift] = true
+	}
+
+	// Custom lifts: unique names and aliases that don't shadow other lifts
+	names := make(map[string]Lift)
+	claim := func(field, name string, lift Lift) {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if standard, err := ParseLift(key); err == nil {
+			add(field, "%q is already the name of %s", name, standard)
+		} else if other, ok := names[key]; ok && other != lift {
+			add(field, "%q is already the name of %s", name, other)
+		} else if ok {
+			add(field, "%q is listed twice", name)
+		}
+		names[key] = lift
+	}
+	for i, custom := range c.CustomLifts {
+		field := fmt.Sprintf("CustomLifts[%d]", i)
+		if strings.TrimSpace(string(custom.Name)) == "" {
+			add(field+".Name", "required")
+			continue
+		}
+		claim(field+".Name", string(custom.Name), custom.Name)
+		for j, alias := range custom.Aliases {
+			claim(fmt.Sprintf("%s.Aliases[%d]", field, j), alias, custom.Name)
+		}
+		known[custom.Name] = true
+	}
+	for i, custom := range c.CustomLifts {
+		if custom.Opposite != "" && !known[custom.Opposite] {
+			add(fmt.Sprintf("CustomLifts[%d].Opposite", i), "unknown lift %q", custom.Opposite)
+		}
+	}
+
+	// Lift order: known lifts, each on exactly one day
+	if len(c.LiftOrder) == 0 {
+		add("LiftOrder", "at least one lift is required")
+	}
+	seenDay := make(map[Lift]int)
+	for i, lift := range c.LiftOrder {
+		field := fmt.Sprintf("LiftOrder[%d]", i)
+		if !known[lift] {
+			add(field, "unknown lift %q", lift)
+			continue
+		}
+		if day, ok := seenDay[lift]; ok {
+			add(field, "%s is already programmed on day %d", lift, day)
+			continue
+		}
+		seenDay[lift] = i + 1
+	}
+
+	// Cycle length and deloads
+	if c.CycleWeeks < 1 || c.CycleWeeks > 3 {
+		add("CycleWeeks", "must be between 1 and 3, got %d", c.CycleWeeks)
+	}
+	if c.Cycle < 1 {
+		add("Cycle", "must be at least 1, got %d", c.Cycle)
+	}
+	switch c.Deload.Mode {
+	case DeloadStandard, DeloadAlways, DeloadNever:
+	case DeloadEvery:
+		if c.Deload.Every < 1 {
+			add("Deload.Every", "must be at least 1 cycle, got %d", c.Deload.Every)
+		}
+	default:
+		add("Deload.Mode", "must be %q, %q, %q or empty, got %q", DeloadAlways, DeloadNever, DeloadEvery, c.Deload.Mode)
+	}
+
+	// Training frequency
+	switch c.DaysPerWeek {
+	case 2, 3, 4:
+	default:
+		add("DaysPerWeek", "must be 2, 3 or 4, got %d", c.DaysPerWeek)
+	}
+
+	// Training maxes: known lifts with non-negative values (0 is unset)
+	for lift, max := range c.TrainingMaxes {
+		field := "TrainingMaxes." + string(lift)
+		if !known[lift] {
+			add(field, "unknown lift %q", lift)
+		} else if max < 0 {
+			add(field, "training max must not be negative, got %g", max)
+		}
+	}
+	needsMax := make(map[Lift]bool)
+	for _, lift := range c.LiftOrder {
+		if known[lift] {
+			needsMax[lift] = true
+		}
+		if paired, ok := c.BBBPairing[lift]; ok && known[paired] {
+			needsMax[paired] = true
+		}
+	}
+	for _, lift := range c.Lifts() {
+		if needsMax[lift] && c.TrainingMaxes[lift] == 0 {
+			add("TrainingMaxes."+string(lift), "missing training max")
+		}
+	}
+
+	// BBB percentage
+	if c.BBBPercentage <= 0 || c.BBBPercentage > 100 {
+		add("BBBPercentage", "must be between 0 and 100, got %g", c.BBBPercentage)
+	}
+
+	for i, pct := range c.BBBWeekPercentages {
+		if pct <= 0 || pct > 100 {
+			add(fmt.Sprintf("BBBWeekPercentages[%d]", i), "must be between 0 and 100, got %g", pct)
+		}
+	}
+	if challenge := c.BBBChallenge; challenge != nil {
+		if len(c.BBBWeekPercentages) > 0 {
+			add("BBBChallenge", "cannot be combined with BBBWeekPercentages")
+		}
+		if len(challenge.Percentages) == 0 {
+			add("BBBChallenge.Percentages", "at least one month is required")
+		}
+		for i, pct := range challenge.Percentages {
+			if pct <= 0 || pct > 100 {
+				add(fmt.Sprintf("BBBChallenge.Percentages[%d]", i), "must be between 0 and 100, got %g", pct)
+			}
+		}
+		if challenge.Month < 1 || challenge.Month > len(challenge.Percentages) {
+			add("BBBChallenge.Month", "must be between 1 and %d, got %d", len(challenge.Percentages), challenge.Month)
+		}
+	}
+
+	// 7th week of a single cycle
+	switch c.SeventhWeek {
+	case SeventhWeekNone, SeventhWeekDeload, SeventhWeekTMTest:
+	default:
+		add("SeventhWeek", "must be %q, %q or empty, got %q", SeventhWeekDeload, SeventhWeekTMTest, c.SeventhWeek)
+	}
+	if c.SeventhWeek != SeventhWeekNone && c.Block != nil {
+		add("SeventhWeek", "not used with a block; set Block.SeventhWeek instead")
+	}
+
+	// Leader/anchor block
+	if block := c.Block; block != nil {
+		if block.LeaderCycles < 1 {
+			add("Block.LeaderCycles", "at least one leader cycle is required, got %d", block.LeaderCycles)
+		}
+		if block.AnchorCycles < 0 {
+			add("Block.AnchorCycles", "must not be negative, got %d", block.AnchorCycles)
+		}
+		if block.AnchorCycles > 0 && block.AnchorTemplate == "" {
+			add("Block.AnchorTemplate", "required when there are anchor cycles")
+		}
+		switch block.SeventhWeek {
+		case SeventhWeekNone, SeventhWeekDeload, SeventhWeekTMTest:
+		default:
+			add("Block.SeventhWeek", "must be %q, %q or empty, got %q", SeventhWeekDeload, SeventhWeekTMTest, block.SeventhWeek)
+		}
+	}
+
+	// FSL/SSL supplemental volume
+	if c.Supplemental.Sets < 1 {
+		add("Supplemental.Sets", "must be at least 1, got %d", c.Supplemental.Sets)
+	}
+	if c.Supplemental.Reps < 1 {
+		add("Supplemental.Reps", "must be at least 1, got %d", c.Supplemental.Reps)
+	}
+
+	// BBB pairing: every programmed lift paired with a known lift
+	for _, lift := range c.LiftOrder {
+		if !known[lift] {
+			continue
+		}
+		if _, ok := c.BBBPairing[lift]; !ok {
+			add("BBBPairing."+string(lift), "missing BBB lift")
+		}
+	}
+	for lift, paired := range c.BBBPairing {
+		field := "BBBPairing." + string(lift)
+		if !known[lift] {
+			add(field, "unknown lift %q", lift)
+		} else if !known[paired] {
+			add(field, "unknown BBB lift %q", paired)
+		}
+	}
+
+	// Accessories: known lifts and exercises from the library
+	knownAccessories := make(map[string]bool)
+	for _, ex := range ActiveLibrary().Exercises {
+		knownAccessories[ex.Name] = true
+	}
+	for _, custom := range c.CustomLifts {
+		for _, name := range custom.Accessories {
+			knownAccessories[name] = true
+		}
+	}
+	for lift, accessory := range c.Accessories {
+		field := "Accessories." + string(lift)
+		if !known[lift] {
+			add(field, "unknown lift %q", lift)
+		} else if accessory != "" && !knownAccessories[accessory] {
+			add(field, "unknown accessory %q", accessory)
+		}
+	}
+
+	// Assistance: known lifts and complete prescriptions
+	for lift, work := range c.Assistance {
+		if !known[lift] {
+			add("Assistance."+string(lift), "unknown lift %q", lift)
+			continue
+		}
+		for i, a := range work {
+			field := fmt.Sprintf("Assistance.%s[%d]", lift, i)
+			if strings.TrimSpace(a.Exercise) == "" {
+				add(field+".Exercise", "required")
+			}
+			switch a.Category {
+			case "", AssistancePush, AssistancePull, AssistanceSingleLegCore:
+			default:
+				add(field+".Category", "must be %q, %q, %q or empty, got %q", AssistancePush, AssistancePull, AssistanceSingleLegCore, a.Category)
+			}
+			if a.Sets < 1 {
+				add(field+".Sets", "must be at least 1, got %d", a.Sets)
+			}
+			if target := a.TotalReps; target != nil {
+				if target.Min < 1 || target.Max < target.Min {
+					add(field+".TotalReps", "must be a range like 50-100, got %d-%d", target.Min, target.Max)
+				}
+			} else if a.Reps < 1 {
+				add(field+".Reps", "must be at least 1 without a TotalReps target, got %d", a.Reps)
+			}
+			if a.Weight < 0 {
+				add(field+".Weight", "must not be negative, got %g", a.Weight)
+			}
+			if a.Rest < 0 {
+				add(field+".Rest", "must not be negative, got %d", a.Rest)
+			}
+		}
+	}
+
+	// Warmups: known lifts and sets below the training max
+	for lift, sets := range c.Warmups {
+		if !known[lift] {
+			add("Warmups."+string(lift), "unknown lift %q", lift)
+			continue
+		}
+		for i, set := range sets {
+			field := fmt.Sprintf("Warmups.%s[%d]", lift, i)
+			if set.Percentage < 0 || set.Percentage >= 100 {
+				add(field+".Percentage", "must be between 0 (empty bar) and 100, got %g", set.Percentage)
+			}
+			if set.Reps < 1 {
+				add(field+".Reps", "must be at least 1, got %d", set.Reps)
+			}
+		}
+	}
+
+	// Conditioning: known lifts and a reps, duration or distance per set
+	for lift, work := range c.Conditioning {
+		if !known[lift] {
+			add("Conditioning."+string(lift), "unknown lift %q", lift)
+			continue
+		}
+		for i, entry := range work {
+			field := fmt.Sprintf("Conditioning.%s[%d]", lift, i)
+			if strings.TrimSpace(entry.Exercise) == "" {
+				add(field+".Exercise", "required")
+			}
+			switch entry.Kind {
+			case ConditioningJumps, ConditioningThrows, ConditioningCardio:
+			default:
+				add(field+".Kind", "must be %q, %q or %q, got %q", ConditioningJumps, ConditioningThrows, ConditioningCardio, entry.Kind)
+			}
+			if entry.Sets < 1 {
+				add(field+".Sets", "must be at least 1, got %d", entry.Sets)
+			}
+			if entry.Reps < 0 || entry.Duration < 0 || entry.Distance < 0 {
+				add(field, "reps, duration and distance must not be negative")
+			} else if entry.Reps == 0 && entry.Duration == 0 && entry.Distance == 0 {
+				add(field, "needs reps, a duration or a distance per set")
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	// Sort by field so output is stable across map iteration
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
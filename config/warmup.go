@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lifting/plates"
+)
+
+// WarmupSet is one warm-up set at a percentage of the training max. A zero
+// Percentage is the empty bar.
+type WarmupSet struct {
+	Percentage float64
+	Reps       int
+}
+
+// DefaultWarmup is the standard 5/3/1 warm-up: 40% x5, 50% x5, 60% x3
+var DefaultWarmup = []WarmupSet{
+	{Percentage: 40, Reps: 5},
+	{Percentage: 50, Reps: 5},
+	{Percentage: 60, Reps: 3},
+}
+
+// WarmupFor returns the warm-up sets for a lift: its Warmups entry (empty
+// for no warm-ups) or DefaultWarmup
+func (c *Config) WarmupFor(lift Lift) []WarmupSet {
+	if sets, ok := c.Warmups[lift]; ok {
+		return sets
+	}
+	return DefaultWarmup
+}
+
+// BarWeight returns the weight of the bar: the plate inventory's bar, or a
+// standard bar for the config's units
+func (c *Config) BarWeight() float64 {
+	if c.Plates != nil {
+		return c.Plates.BarWeight
+	}
+	return plates.Standard(string(c.Units)).BarWeight
+}
+
+// ParseWarmup parses a comma-separated warm-up such as "bar x10, 30x5, 50x5",
+// where each set is PERCENTxREPS or barxREPS. "none" means no warm-up sets.
+func ParseWarmup(value string) ([]WarmupSet, error) {
+	sets := []WarmupSet{}
+	if strings.EqualFold(strings.TrimSpace(value), "none") {
+		return sets, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToLower(strings.ReplaceAll(item, " ", ""))
+		if item == "" {
+			continue
+		}
+		pctStr, repsStr, ok := strings.Cut(item, "x")
+		if !ok {
+			return nil, fmt.Errorf("expected PERCENTxREPS or barxREPS, got %q", item)
+		}
+
+		var set WarmupSet
+		var err error
+		if pctStr != "bar" {
+			if set.Percentage, err = strconv.ParseFloat(strings.TrimSuffix(pctStr, "%"), 64); err != nil {
+				return nil, fmt.Errorf("invalid percentage in %q", item)
+			}
+		}
+		if set.Reps, err = strconv.Atoi(repsStr); err != nil {
+			return nil, fmt.Errorf("invalid reps in %q", item)
+		}
+		sets = append(sets, set)
+	}
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("no warm-up sets in %q", value)
+	}
+	return sets, nil
+}
@@ -0,0 +1,402 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// yamlNode is a parsed YAML value and the line it starts on
+type yamlNode struct {
+	line   int
+	scalar any         // for scalars: string, float64, bool or nil
+	keys   []string    // for mappings, in file order
+	keyAt  []int       // line of each mapping key
+	values []*yamlNode // mapping values, or sequence items
+	kind   byte        // 's'calar, 'm'apping or 'l'ist
+}
+
+// yamlLine is one meaningful line of a YAML document
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// yamlToJSON converts the subset of YAML a config file needs (block
+// mappings and sequences, flow [lists] and {maps}, quoted and plain scalars,
+// comments) to JSON. Every value keeps its line number, so JSON decoding
+// errors and field lines point at the YAML source. A JSON document, which is
+// also YAML, is returned unchanged.
+func yamlToJSON(data []byte) ([]byte, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return data, nil
+	}
+
+	var lines []yamlLine
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimRight(stripYAMLComment(raw), " \t\r")
+		text := strings.TrimLeft(raw, " ")
+		if text == "" || text == "---" {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		lines = append(lines, yamlLine{num: i + 1, indent: len(raw) - len(text), text: text})
+	}
+	if len(lines) == 0 {
+		return []byte("{}"), nil
+	}
+
+	p := &yamlParser{lines: lines}
+	node, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+
+	w := &yamlWriter{line: 1}
+	w.value(node)
+	return w.buf.Bytes(), nil
+}
+
+// stripYAMLComment removes a # comment that is outside quotes
+func stripYAMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// yamlParser parses block structure from indented lines
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block parses the mapping or sequence whose entries start at indent
+func (p *yamlParser) block(indent int) (*yamlNode, error) {
+	first := p.lines[p.pos]
+	if isYAMLItem(first.text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+// isYAMLItem reports whether a line starts a sequence item
+func isYAMLItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *yamlParser) sequence(indent int) (*yamlNode, error) {
+	node := &yamlNode{kind: 'l', line: p.lines[p.pos].num}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent || !isYAMLItem(line.text) {
+			return nil, fmt.Errorf("line %d: expected a \"- \" list item", line.num)
+		}
+
+		rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		if rest == "" {
+			// The item's value is the block on the following lines
+			p.pos++
+			item, err := p.nested(line, indent)
+			if err != nil {
+				return nil, err
+			}
+			node.values = append(node.values, item)
+			continue
+		}
+
+		// A "key: value" or nested "- " item starts a block of its own, so
+		// "- Exercise: Dips" begins a mapping item; anything else is a value
+		if _, _, err := splitYAMLKey(rest); (err != nil || strings.ContainsAny(rest[:1], "[{")) && !isYAMLItem(rest) {
+			p.pos++
+			item, err := parseYAMLFlow(rest, line.num)
+			if err != nil {
+				return nil, err
+			}
+			node.values = append(node.values, item)
+			continue
+		}
+		p.lines[p.pos] = yamlLine{num: line.num, indent: line.indent + len(line.text) - len(rest), text: rest}
+		item, err := p.block(p.lines[p.pos].indent)
+		if err != nil {
+			return nil, err
+		}
+		node.values = append(node.values, item)
+	}
+	return node, nil
+}
+
+func (p *yamlParser) mapping(indent int) (*yamlNode, error) {
+	node := &yamlNode{kind: 'm', line: p.lines[p.pos].num}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+		if isYAMLItem(line.text) {
+			return nil, fmt.Errorf("line %d: unexpected list item", line.num)
+		}
+
+		key, rest, err := splitYAMLKey(line.text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.num, err)
+		}
+		p.pos++
+
+		var value *yamlNode
+		if rest == "" {
+			value, err = p.nested(line, indent)
+		} else {
+			value, err = parseYAMLFlow(rest, line.num)
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.keys = append(node.keys, key)
+		node.keyAt = append(node.keyAt, line.num)
+		node.values = append(node.values, value)
+	}
+	return node, nil
+}
+
+// nested parses the block under a "key:" or "-" line: more deeply indented
+// lines, a sequence at the key's own indent, or null when there are none
+func (p *yamlParser) nested(parent yamlLine, indent int) (*yamlNode, error) {
+	if p.pos < len(p.lines) {
+		next := p.lines[p.pos]
+		if next.indent > indent || (next.indent == indent && isYAMLItem(next.text) && !isYAMLItem(parent.text)) {
+			return p.block(next.indent)
+		}
+	}
+	return &yamlNode{kind: 's', line: parent.num}, nil
+}
+
+// splitYAMLKey splits a "key: value" line into its key and the value text
+func splitYAMLKey(text string) (string, string, error) {
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated quoted key")
+		}
+		key, err := unquoteYAML(text[:end+1])
+		if err != nil {
+			return "", "", err
+		}
+		rest, ok := strings.CutPrefix(strings.TrimLeft(text[end+1:], " "), ":")
+		if !ok {
+			return "", "", fmt.Errorf("expected \":\" after key %q", key)
+		}
+		return key, strings.TrimSpace(rest), nil
+	}
+
+	if i := strings.Index(text, ": "); i >= 0 {
+		return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+2:]), nil
+	}
+	if key, ok := strings.CutSuffix(text, ":"); ok {
+		return strings.TrimSpace(key), "", nil
+	}
+	return "", "", fmt.Errorf("expected \"key: value\", got %q", text)
+}
+
+// closingQuote returns the index of the quote closing the one at text[0]
+func closingQuote(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote && quote == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteYAML decodes a single- or double-quoted YAML string
+func unquoteYAML(text string) (string, error) {
+	if text[0] == '\'' {
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	}
+	value, err := strconv.Unquote(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid quoted string %s", text)
+	}
+	return value, nil
+}
+
+// parseYAMLFlow parses a value written on one line: a scalar, or a flow
+// [list] or {map} of them
+func parseYAMLFlow(text string, line int) (*yamlNode, error) {
+	text = strings.TrimSpace(text)
+	switch {
+	case text == "|" || text == ">" || strings.HasPrefix(text, "|") || strings.HasPrefix(text, ">"):
+		return nil, fmt.Errorf("line %d: block scalars (| and >) are not supported", line)
+	case strings.HasPrefix(text, "&") || strings.HasPrefix(text, "*"):
+		return nil, fmt.Errorf("line %d: anchors and aliases are not supported", line)
+	case strings.HasPrefix(text, "["):
+		items, err := splitYAMLFlow(text, '[', ']', line)
+		if err != nil {
+			return nil, err
+		}
+		node := &yamlNode{kind: 'l', line: line}
+		for _, item := range items {
+			value, err := parseYAMLFlow(item, line)
+			if err != nil {
+				return nil, err
+			}
+			node.values = append(node.values, value)
+		}
+		return node, nil
+	case strings.HasPrefix(text, "{"):
+		items, err := splitYAMLFlow(text, '{', '}', line)
+		if err != nil {
+			return nil, err
+		}
+		node := &yamlNode{kind: 'm', line: line}
+		for _, item := range items {
+			key, rest, err := splitYAMLKey(item)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			value, err := parseYAMLFlow(rest, line)
+			if err != nil {
+				return nil, err
+			}
+			node.keys = append(node.keys, key)
+			node.keyAt = append(node.keyAt, line)
+			node.values = append(node.values, value)
+		}
+		return node, nil
+	}
+
+	node := &yamlNode{kind: 's', line: line}
+	switch {
+	case text[0] == '"' || text[0] == '\'':
+		if closingQuote(text) != len(text)-1 {
+			return nil, fmt.Errorf("line %d: invalid quoted string %s", line, text)
+		}
+		value, err := unquoteYAML(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		node.scalar = value
+	case text == "~" || text == "null" || text == "Null" || text == "NULL":
+	case text == "true" || text == "True" || text == "TRUE":
+		node.scalar = true
+	case text == "false" || text == "False" || text == "FALSE":
+		node.scalar = false
+	default:
+		if number, err := strconv.ParseFloat(text, 64); err == nil && !math.IsInf(number, 0) && !math.IsNaN(number) && !strings.ContainsAny(text, "xX_") {
+			node.scalar = number
+		} else {
+			node.scalar = text
+		}
+	}
+	return node, nil
+}
+
+// splitYAMLFlow splits the comma-separated items of a flow collection,
+// leaving nested collections and quoted strings intact
+func splitYAMLFlow(text string, open, close byte, line int) ([]string, error) {
+	if text[len(text)-1] != close {
+		return nil, fmt.Errorf("line %d: expected %q at the end of %s", line, close, text)
+	}
+	inner := strings.TrimSpace(text[1 : len(text)-1])
+	if inner == "" {
+		return nil, nil
+	}
+
+	var items []string
+	depth, start := 0, 0
+	for i := 0; i < len(inner); i++ {
+		switch c := inner[i]; {
+		case c == '"' || c == '\'':
+			end := closingQuote(inner[i:])
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated quoted string", line)
+			}
+			i += end
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			items = append(items, inner[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, inner[start:]), nil
+}
+
+// yamlWriter writes a parsed YAML tree as JSON, starting each key and list
+// item on the line it had in the YAML source
+type yamlWriter struct {
+	buf  bytes.Buffer
+	line int
+}
+
+// moveTo adds newlines until the output reaches line
+func (w *yamlWriter) moveTo(line int) {
+	for ; w.line < line; w.line++ {
+		w.buf.WriteByte('\n')
+	}
+}
+
+func (w *yamlWriter) value(node *yamlNode) {
+	switch node.kind {
+	case 'm':
+		w.buf.WriteByte('{')
+		for i, key := range node.keys {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.moveTo(node.keyAt[i])
+			encoded, _ := json.Marshal(key)
+			w.buf.Write(encoded)
+			w.buf.WriteByte(':')
+			w.value(node.values[i])
+		}
+		w.buf.WriteByte('}')
+	case 'l':
+		w.buf.WriteByte('[')
+		for i, item := range node.values {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.moveTo(item.line)
+			w.value(item)
+		}
+		w.buf.WriteByte(']')
+	default:
+		w.moveTo(node.line)
+		encoded, _ := json.Marshal(node.scalar)
+		w.buf.Write(encoded)
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestLoadFileYAML checks a YAML config loads the same as its JSON form
+func TestLoadFileYAML(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "531.yaml")
+	jsonPath := filepath.Join(dir, "531.json")
+	writeFile(t, yamlPath, `# Kilogram FSL setup
+version: 1
+Units: kg
+Template: "fsl"   # first set last
+TrainingMaxes:
+  squat: 140
+  bench: 100.5
+  deadlift: 180
+  ohp: 60
+LiftOrder: [squat, bench, deadlift, ohp]
+Supplemental: {Sets: 3, Reps: 8}
+WarmupOnDeload: true
+Assistance:
+  bench:
+    - Exercise: Dips
+      Sets: 5
+      Reps: 10
+      Weight: 10
+    - {Exercise: 'Chin-up', Sets: 5, TotalReps: {Min: 50, Max: 100}}
+Warmups:
+  squat:
+  - {Percentage: 0, Reps: 10}
+  - Percentage: 50
+    Reps: 5
+`)
+	writeFile(t, jsonPath, `{
+  "version": 1,
+  "Units": "kg",
+  "Template": "fsl",
+  "TrainingMaxes": {"squat": 140, "bench": 100.5, "deadlift": 180, "ohp": 60},
+  "LiftOrder": ["squat", "bench", "deadlift", "ohp"],
+  "Supplemental": {"Sets": 3, "Reps": 8},
+  "WarmupOnDeload": true,
+  "Assistance": {"bench": [
+    {"Exercise": "Dips", "Sets": 5, "Reps": 10, "Weight": 10},
+    {"Exercise": "Chin-up", "Sets": 5, "TotalReps": {"Min": 50, "Max": 100}}
+  ]},
+  "Warmups": {"squat": [{"Percentage": 0, "Reps": 10}, {"Percentage": 50, "Reps": 5}]}
+}`)
+
+	fromYAML, err := LoadFile(yamlPath)
+	if err != nil {
+		t.Fatalf("LoadFile(yaml): %v", err)
+	}
+	fromJSON, err := LoadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("LoadFile(json): %v", err)
+	}
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("YAML config = %+v, want %+v", fromYAML, fromJSON)
+	}
+}
+
+// TestLoadFileYAMLErrors checks YAML config errors point at the field and
+// line in the YAML source
+func TestLoadFileYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		line  int
+		field string
+	}{
+		{"unknown field", "Units: lbs\nUnitz: kg\n", 2, "Unitz"},
+		{"wrong type", "TrainingMaxes:\n  squat: 315\nCycle: first\n", 3, "Cycle"},
+		{"invalid value", "TrainingMaxes:\n  squat: 315\n  bench: -5\n", 3, "TrainingMaxes.bench"},
+		{"bad indentation", "TrainingMaxes:\n  squat: 315\n    bench: 225\n", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "531.yml")
+			writeFile(t, path, tt.body)
+
+			_, err := LoadFile(path)
+			var fileErr *FileError
+			if !errors.As(err, &fileErr) {
+				t.Fatalf("LoadFile error = %v, want a FileError", err)
+			}
+			if fileErr.Line != tt.line || fileErr.Field != tt.field {
+				t.Errorf("error at line %d field %q, want line %d field %q (%v)", fileErr.Line, fileErr.Field, tt.line, tt.field, err)
+			}
+		})
+	}
+}
+
+// writeFile writes a test file
+func writeFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
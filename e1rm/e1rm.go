@@ -0,0 +1,88 @@
+// Package e1rm estimates one-rep maxes from weight x reps results, such as a
+// recent AMRAP set
+package e1rm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Formula is an estimated 1RM formula
+type Formula string
+
+const (
+	Wendler Formula = "wendler" // weight x reps x 0.0333 + weight, from 5/3/1
+	Epley   Formula = "epley"   // weight x (1 + reps/30)
+	Brzycki Formula = "brzycki" // weight x 36 / (37 - reps)
+)
+
+// DefaultFormula is the formula used when none is chosen
+const DefaultFormula = Wendler
+
+// Formulas lists every supported formula, default first
+var Formulas = []Formula{Wendler, Epley, Brzycki}
+
+// ParseFormula parses a formula name; an empty name is DefaultFormula
+func ParseFormula(name string) (Formula, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DefaultFormula, nil
+	}
+	for _, f := range Formulas {
+		if name == string(f) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown e1RM formula %q (want wendler, epley or brzycki)", name)
+}
+
+// Result is a set of Reps completed at Weight
+type Result struct {
+	Weight float64
+	Reps   int
+}
+
+// ParseResult parses a result written as WEIGHTxREPS, e.g. "225x8"
+func ParseResult(value string) (Result, error) {
+	weightStr, repsStr, ok := strings.Cut(strings.ToLower(strings.ReplaceAll(value, " ", "")), "x")
+	if !ok {
+		return Result{}, fmt.Errorf("expected WEIGHTxREPS, got %q", value)
+	}
+	weight, err := strconv.ParseFloat(weightStr, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid weight in %q", value)
+	}
+	reps, err := strconv.Atoi(repsStr)
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid reps in %q", value)
+	}
+	return Result{Weight: weight, Reps: reps}, nil
+}
+
+// Estimate returns the estimated 1RM for a result. A single is its own 1RM.
+func (f Formula) Estimate(r Result) (float64, error) {
+	if r.Weight <= 0 {
+		return 0, fmt.Errorf("weight must be positive, got %g", r.Weight)
+	}
+	if r.Reps < 1 {
+		return 0, fmt.Errorf("reps must be at least 1, got %d", r.Reps)
+	}
+	if r.Reps == 1 {
+		return r.Weight, nil
+	}
+
+	switch f {
+	case Wendler:
+		return r.Weight*float64(r.Reps)*0.0333 + r.Weight, nil
+	case Epley:
+		return r.Weight * (1 + float64(r.Reps)/30), nil
+	case Brzycki:
+		if r.Reps >= 37 {
+			return 0, fmt.Errorf("the Brzycki formula needs fewer than 37 reps, got %d", r.Reps)
+		}
+		return r.Weight * 36 / float64(37-r.Reps), nil
+	default:
+		return 0, fmt.Errorf("unknown e1RM formula %q", f)
+	}
+}
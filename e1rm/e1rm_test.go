@@ -0,0 +1,61 @@
+package e1rm
+
+import (
+	"math"
+	"testing"
+)
+
+// TestEstimate checks each formula against hand-worked estimates, and that
+// results no formula can estimate from are refused
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula Formula
+		result  Result
+		want    float64
+		wantErr bool
+	}{
+		{name: "wendler", formula: Wendler, result: Result{Weight: 200, Reps: 5}, want: 233.3},
+		{name: "epley", formula: Epley, result: Result{Weight: 300, Reps: 10}, want: 400},
+		{name: "brzycki", formula: Brzycki, result: Result{Weight: 200, Reps: 5}, want: 225},
+		{name: "wendler single", formula: Wendler, result: Result{Weight: 315, Reps: 1}, want: 315},
+		{name: "epley single", formula: Epley, result: Result{Weight: 315, Reps: 1}, want: 315},
+		{name: "brzycki single", formula: Brzycki, result: Result{Weight: 315, Reps: 1}, want: 315},
+		{name: "zero reps", formula: Wendler, result: Result{Weight: 200, Reps: 0}, wantErr: true},
+		{name: "negative reps", formula: Epley, result: Result{Weight: 200, Reps: -3}, wantErr: true},
+		{name: "zero weight", formula: Brzycki, result: Result{Weight: 0, Reps: 5}, wantErr: true},
+		{name: "brzycki 37 reps", formula: Brzycki, result: Result{Weight: 50, Reps: 37}, wantErr: true},
+		{name: "unknown formula", formula: "lombardi", result: Result{Weight: 200, Reps: 5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.formula.Estimate(tt.result)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Estimate(%+v) = %g, want an error", tt.result, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Estimate(%+v): %v", tt.result, err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Estimate(%+v) = %g, want %g", tt.result, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseFormula checks formula names are matched case-insensitively and
+// an empty name is the default
+func TestParseFormula(t *testing.T) {
+	for name, want := range map[string]Formula{"": DefaultFormula, "wendler": Wendler, " Epley ": Epley, "BRZYCKI": Brzycki} {
+		if got, err := ParseFormula(name); err != nil || got != want {
+			t.Errorf("ParseFormula(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormula("lombardi"); err == nil {
+		t.Error("ParseFormula(\"lombardi\") succeeded, want an error")
+	}
+}
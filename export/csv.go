@@ -3,11 +3,20 @@ package export
 import (
 	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
+	"lifting/plates"
 	"lifting/program"
 )
 
+// DefaultFilename returns the default CSV filename for a program, e.g. "531_bbb.csv"
+func DefaultFilename(prog *program.Program) string {
+	return strings.ToLower(strings.ReplaceAll(prog.Label(), " ", "_")) + ".csv"
+}
+
 // ToCSV exports the program to a CSV file
 func ToCSV(prog *program.Program, filename string) error {
 	file, err := os.Create(filename)
@@ -16,11 +25,15 @@ func ToCSV(prog *program.Program, filename string) error {
 	}
 	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	return WriteCSV(file, prog)
+}
 
-	// Write header
-	header := []string{"Week", "Day", "Exercise", "Sets", "Reps", "Weight", "Percentage"}
+// WriteCSV writes the program as CSV to the given writer
+func WriteCSV(w io.Writer, prog *program.Program) error {
+	writer := csv.NewWriter(w)
+
+	// Write header (new columns go at the end so existing readers keep working)
+	header := []string{"Week", "Day", "Exercise", "Sets", "Reps", fmt.Sprintf("Weight (%s)", prog.Units), "Percentage", "Plates (per side)", "Type", "Category", "Total Reps", "Rest (s)", "Duration (s)", "Distance (m)", "Cycle"}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
@@ -28,35 +41,65 @@ func ToCSV(prog *program.Program, filename string) error {
 	// Write each day's sets
 	for _, day := range prog.Days {
 		for _, set := range day.Sets {
-			row := formatRow(day.Week, day.DayNum, set)
+			row := formatRow(day, set)
 			if err := writer.Write(row); err != nil {
 				return fmt.Errorf("failed to write row: %w", err)
 			}
 		}
 	}
 
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+
 	return nil
 }
 
 // formatRow formats a set as a CSV row
-func formatRow(week, day int, set program.Set) []string {
+func formatRow(day program.Day, set program.Set) []string {
 	// Format weight and percentage (blank for accessories)
 	weightStr := ""
 	pctStr := ""
 	if set.Weight > 0 {
-		weightStr = fmt.Sprintf("%.0f", set.Weight)
+		// Keep fractional kg weights like 102.5 without padding whole ones
+		weightStr = strconv.FormatFloat(set.Weight, 'f', -1, 64)
 	}
 	if set.Percentage > 0 {
 		pctStr = fmt.Sprintf("%.0f%%", set.Percentage)
 	}
+	platesStr := ""
+	if set.Plates != nil {
+		platesStr = plates.Format(set.Plates)
+	}
+	restStr := ""
+	if set.Rest > 0 {
+		restStr = strconv.Itoa(set.Rest)
+	}
+	durationStr := ""
+	if set.Duration > 0 {
+		durationStr = strconv.Itoa(set.Duration)
+	}
+	distanceStr := ""
+	if set.Distance > 0 {
+		distanceStr = strconv.FormatFloat(set.Distance, 'f', -1, 64)
+	}
 
 	return []string{
-		fmt.Sprintf("%d", week),
-		fmt.Sprintf("%d", day),
+		fmt.Sprintf("%d", day.Week),
+		fmt.Sprintf("%d", day.DayNum),
 		set.Exercise,
 		fmt.Sprintf("%d", set.Sets),
 		set.Reps,
 		weightStr,
 		pctStr,
+		platesStr,
+		string(set.Kind),
+		string(set.Category),
+		set.TotalReps,
+		restStr,
+		durationStr,
+		distanceStr,
+		fmt.Sprintf("%d", day.Cycle),
 	}
 }
@@ -0,0 +1,97 @@
+package hevy
+
+import (
+	"time"
+
+	"lifting/config"
+	"lifting/program"
+)
+
+// AMRAPResult is the reps logged in Hevy on one AMRAP set of a program
+type AMRAPResult struct {
+	Cycle        int
+	Week         int // calendar week of the cycle
+	DayNum       int
+	Lift         config.Lift
+	Percentage   float64
+	Weight       float64 // prescribed weight, in the program's units
+	Prescribed   string  // prescribed reps, e.g. "5+"
+	Reps         int     // reps logged
+	LoggedWeight float64 // weight logged, in the program's units
+	WorkoutID    string
+	Completed    time.Time
+}
+
+// MatchAMRAPResults finds the workouts logged from a program's routines and
+// returns the reps achieved on each AMRAP set, in program order.
+//
+// A workout belongs to a day when it has the day's routine title; if a day
+// was logged more than once, the latest workout wins. Logged sets are matched
+// to the routine by position, grouping sets into exercises the same way
+// ConvertDayToRoutine does, so AMRAP sets left unlogged are skipped.
+func MatchAMRAPResults(prog *program.Program, workouts []Workout, mapper *ExerciseMapper) ([]AMRAPResult, error) {
+	latest := make(map[string]Workout) // routine title -> latest workout
+	for _, w := range workouts {
+		if existing, ok := latest[w.Title]; !ok || w.StartTime.After(existing.StartTime) {
+			latest[w.Title] = w
+		}
+	}
+
+	var results []AMRAPResult
+	for _, day := range prog.Days {
+		workout, ok := latest[prog.RoutineTitle(day)]
+		if !ok {
+			continue
+		}
+
+		// Logged exercises by template, in workout order
+		logged := make(map[string][]WorkoutExercise)
+		for _, ex := range workout.Exercises {
+			logged[ex.ExerciseTemplateID] = append(logged[ex.ExerciseTemplateID], ex)
+		}
+
+		seen := make(map[string]int) // template ID -> routine exercises so far
+		currentExercise := ""
+		var current *WorkoutExercise
+		setIndex := 0
+		for _, set := range day.Sets {
+			if set.Exercise != currentExercise {
+				template, err := mapper.FindTemplate(set.Exercise)
+				if err != nil {
+					return nil, err
+				}
+				current = nil
+				if n := seen[template.ID]; n < len(logged[template.ID]) {
+					current = &logged[template.ID][n]
+				}
+				seen[template.ID]++
+				currentExercise = set.Exercise
+				setIndex = 0
+			}
+
+			if set.Kind == program.SetAMRAP && current != nil && setIndex < len(current.Sets) {
+				if loggedSet := current.Sets[setIndex]; loggedSet.Reps != nil {
+					result := AMRAPResult{
+						Cycle:      day.Cycle,
+						Week:       day.Week,
+						DayNum:     day.DayNum,
+						Lift:       config.Lift(set.Exercise),
+						Percentage: set.Percentage,
+						Weight:     set.Weight,
+						Prescribed: set.Reps,
+						Reps:       *loggedSet.Reps,
+						WorkoutID:  workout.ID,
+						Completed:  workout.EndTime,
+					}
+					if loggedSet.WeightKg != nil {
+						result.LoggedWeight = FromKg(*loggedSet.WeightKg, prog.Units)
+					}
+					results = append(results, result)
+				}
+			}
+			setIndex += set.Sets
+		}
+	}
+
+	return results, nil
+}
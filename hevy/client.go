@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"strings"
+
+	"lifting/config"
 )
 
 const baseURL = "https://api.hevyapp.com/v1"
@@ -58,10 +60,12 @@ type RepRange struct {
 
 // RoutineSet represents a set in a routine exercise
 type RoutineSet struct {
-	Type     SetType   `json:"type,omitempty"`
-	WeightKg *float64  `json:"weight_kg,omitempty"`
-	Reps     *int      `json:"reps,omitempty"`
-	RepRange *RepRange `json:"rep_range,omitempty"`
+	Type            SetType   `json:"type,omitempty"`
+	WeightKg        *float64  `json:"weight_kg,omitempty"`
+	Reps            *int      `json:"reps,omitempty"`
+	RepRange        *RepRange `json:"rep_range,omitempty"`
+	DistanceMeters  *int      `json:"distance_meters,omitempty"`
+	DurationSeconds *int      `json:"duration_seconds,omitempty"`
 }
 
 // RoutineExercise represents an exercise in a routine
@@ -377,50 +381,43 @@ func (c *Client) UpdateRoutine(routineID string, routine CreateRoutineRequest) (
 	return &result.Routine, nil
 }
 
-// exerciseAliases maps our exercise names to Hevy's expected names
-var exerciseAliases = map[string][]string{
-	// Main lifts
-	"squat":          {"barbell squat", "squat (barbell)"},
-	"bench press":    {"barbell bench press", "bench press (barbell)"},
-	"deadlift":       {"barbell deadlift", "deadlift (barbell)"},
-	"overhead press": {"overhead press (barbell)", "barbell overhead press", "shoulder press (barbell)"},
-
-	// Accessories
-	"barbell row":         {"bent over row (barbell)", "barbell bent over row", "bent over row"},
-	"dumbbell press":      {"dumbbell bench press", "bench press (dumbbell)", "dumbbell chest press"},
-	"dumbbell row":        {"dumbbell row", "bent over row (dumbbell)", "one arm dumbbell row"},
-	"leg curl":            {"lying leg curl", "leg curl (machine)", "seated leg curl"},
-	"leg press":           {"leg press (machine)", "leg press"},
-	"tricep pushdown":     {"tricep pushdown", "triceps pushdown", "cable pushdown"},
-	"cable fly":           {"cable fly", "cable chest fly", "cable crossover"},
-	"good morning":        {"good morning", "good morning (barbell)"},
-	"hanging leg raise":   {"hanging leg raise", "hanging knee raise"},
-	"back extension":      {"back extension", "hyperextension", "back extension (machine)"},
-	"lateral raise":       {"lateral raise (dumbbell)", "dumbbell lateral raise", "lateral raise"},
-	"face pull":           {"face pull", "face pull (cable)"},
-	"rear delt fly":       {"reverse fly (dumbbell)", "rear delt fly", "reverse fly"},
-	"pull-up":             {"pull up", "pull-up", "pullup"},
-	"dips":                {"dip", "tricep dip", "chest dip"},
-	"lunges":              {"lunge (dumbbell)", "walking lunge", "lunge (barbell)"},
-	"bulgarian split squat": {"bulgarian split squat", "split squat"},
-}
-
 // ExerciseMapper helps map exercise names to Hevy template IDs
 type ExerciseMapper struct {
 	templates map[string]ExerciseTemplate // lowercase title -> template
+	aliases   map[string][]string         // lowercase name -> extra Hevy titles
 }
 
-// NewExerciseMapper creates a mapper from a list of templates
+// NewExerciseMapper creates a mapper from a list of templates, seeded with
+// the Hevy aliases of the active exercise library
 func NewExerciseMapper(templates []ExerciseTemplate) *ExerciseMapper {
 	m := &ExerciseMapper{
 		templates: make(map[string]ExerciseTemplate),
+		aliases:   make(map[string][]string),
 	}
 	for _, t := range templates {
 		m.templates[strings.ToLower(t.Title)] = t
 	}
+	for _, ex := range config.ActiveLibrary().Exercises {
+		m.AddAliases(ex.Name, ex.HevyAliases...)
+	}
 	return m
 }
 
+// AddAliases registers Hevy exercise titles to try for an exercise name
+func (m *ExerciseMapper) AddAliases(name string, aliases ...string) {
+	lower := strings.ToLower(name)
+	for _, alias := range aliases {
+		m.aliases[lower] = append(m.aliases[lower], strings.ToLower(alias))
+	}
+}
+
+// AddCustomLifts registers the Hevy titles of a config's custom lifts
+func (m *ExerciseMapper) AddCustomLifts(cfg *config.Config) {
+	for _, custom := range cfg.CustomLifts {
+		m.AddAliases(string(custom.Name), custom.HevyNames...)
+	}
+}
+
 // FindTemplate finds a template by name (case-insensitive, with aliases)
 func (m *ExerciseMapper) FindTemplate(name string) (*ExerciseTemplate, error) {
 	lower := strings.ToLower(name)
@@ -431,11 +428,9 @@ func (m *ExerciseMapper) FindTemplate(name string) (*ExerciseTemplate, error) {
 	}
 
 	// Try aliases
-	if aliases, ok := exerciseAliases[lower]; ok {
-		for _, alias := range aliases {
-			if t, ok := m.templates[alias]; ok {
-				return &t, nil
-			}
+	for _, alias := range m.aliases[lower] {
+		if t, ok := m.templates[alias]; ok {
+			return &t, nil
 		}
 	}
 
@@ -453,3 +448,24 @@ func (m *ExerciseMapper) FindTemplate(name string) (*ExerciseTemplate, error) {
 func LbsToKg(lbs float64) float64 {
 	return lbs * 0.453592
 }
+
+// ToKg converts a weight in the given unit to kilograms
+func ToKg(weight float64, unit config.Unit) float64 {
+	if unit == config.Kilograms {
+		return weight
+	}
+	return LbsToKg(weight)
+}
+
+// KgToLbs converts kilograms to pounds
+func KgToLbs(kg float64) float64 {
+	return kg / 0.453592
+}
+
+// FromKg converts a weight in kilograms to the given unit
+func FromKg(weightKg float64, unit config.Unit) float64 {
+	if unit == config.Kilograms {
+		return weightKg
+	}
+	return KgToLbs(weightKg)
+}
@@ -2,26 +2,35 @@ package hevy
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
+	"lifting/config"
+	"lifting/plates"
 	"lifting/program"
 )
 
-// ConvertDayToRoutine converts a program Day to a Hevy CreateRoutineRequest
-func ConvertDayToRoutine(day program.Day, mapper *ExerciseMapper) (*CreateRoutineRequest, error) {
-	title := fmt.Sprintf("531 BBB W%dD%d - %s", day.Week, day.DayNum, day.MainLift)
+// ConvertDayToRoutine converts a program Day to a Hevy CreateRoutineRequest.
+// Weights are converted from the program's unit to the kilograms Hevy expects.
+func ConvertDayToRoutine(prog *program.Program, day program.Day, mapper *ExerciseMapper) (*CreateRoutineRequest, error) {
+	title := prog.RoutineTitle(day)
+	unit := prog.Units
 
 	exercises := []RoutineExercise{}
 	currentExercise := ""
 	var currentRoutineExercise *RoutineExercise
+	var currentType string
+	var notes exerciseNotes
 
 	for _, set := range day.Sets {
 		// If we've moved to a new exercise, save the previous one and start a new one
 		if set.Exercise != currentExercise {
 			if currentRoutineExercise != nil {
+				currentRoutineExercise.Notes = notes.render()
 				exercises = append(exercises, *currentRoutineExercise)
 			}
+			notes = exerciseNotes{}
 
 			template, err := mapper.FindTemplate(set.Exercise)
 			if err != nil {
@@ -33,15 +42,29 @@ func ConvertDayToRoutine(day program.Day, mapper *ExerciseMapper) (*CreateRoutin
 				Sets:               []RoutineSet{},
 			}
 			currentExercise = set.Exercise
+			currentType = template.Type
 		}
 
+		if set.Rest > 0 {
+			rest := set.Rest
+			currentRoutineExercise.RestSeconds = &rest
+		}
+
+		notes.add(set, unit)
+
 		// Convert sets
-		routineSets := convertSets(set)
+		routineSets := convertSets(set, unit)
+		if set.IsConditioning() {
+			for i := range routineSets {
+				fitExerciseType(&routineSets[i], currentType)
+			}
+		}
 		currentRoutineExercise.Sets = append(currentRoutineExercise.Sets, routineSets...)
 	}
 
 	// Don't forget the last exercise
 	if currentRoutineExercise != nil {
+		currentRoutineExercise.Notes = notes.render()
 		exercises = append(exercises, *currentRoutineExercise)
 	}
 
@@ -51,20 +74,73 @@ func ConvertDayToRoutine(day program.Day, mapper *ExerciseMapper) (*CreateRoutin
 	}, nil
 }
 
+// exerciseNotes collects the notes for one routine exercise: labels for
+// Joker sets, the plates to load for each distinct weight and any total-rep
+// target
+type exerciseNotes struct {
+	jokers []string
+	plates []string
+	target string
+}
+
+// add records any notes a set needs
+func (n *exerciseNotes) add(set program.Set, unit config.Unit) {
+	if set.Kind == program.SetJoker {
+		n.jokers = append(n.jokers, fmt.Sprintf("%s x%s (%.0f%%)", unit.Format(set.Weight), set.Reps, set.Percentage))
+	}
+	if set.TotalReps != "" {
+		n.target = fmt.Sprintf("Total reps: %s across all sets", set.TotalReps)
+	}
+	if set.Plates != nil {
+		note := fmt.Sprintf("%s: %s", unit.Format(set.Weight), plates.Format(set.Plates))
+		for _, existing := range n.plates {
+			if existing == note {
+				return
+			}
+		}
+		n.plates = append(n.plates, note)
+	}
+}
+
+// render joins the collected notes (nil when there are none)
+func (n *exerciseNotes) render() *string {
+	var sections []string
+	if n.target != "" {
+		sections = append(sections, n.target)
+	}
+	if len(n.jokers) > 0 {
+		sections = append(sections, "Joker sets (optional, only if the top set moved well)\n"+strings.Join(n.jokers, "\n"))
+	}
+	if len(n.plates) > 0 {
+		sections = append(sections, "Plates per side\n"+strings.Join(n.plates, "\n"))
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	joined := strings.Join(sections, "\n\n")
+	return &joined
+}
+
 // convertSets converts a program.Set to one or more RoutineSets
-func convertSets(set program.Set) []RoutineSet {
+func convertSets(set program.Set, unit config.Unit) []RoutineSet {
 	var sets []RoutineSet
 
-	// Determine set type based on percentage (warmup sets are <=60%)
+	// Determine set type from the set kind, falling back to percentage
+	// (warmup sets are <=60%) for sets without one
 	setType := SetTypeNormal
-	if set.Percentage > 0 && set.Percentage <= 60 {
+	switch {
+	case set.Kind == program.SetWarmup:
+		setType = SetTypeWarmup
+	case set.Kind == "" && set.Percentage > 0 && set.Percentage <= 60:
 		setType = SetTypeWarmup
 	}
 
-	// Check if this is an AMRAP set (ends with "+")
+	// Check if this is an AMRAP set (ends with "+") or a rep range ("3-5")
 	isAMRAP := strings.HasSuffix(set.Reps, "+")
 	repsStr := strings.TrimSuffix(set.Reps, "+")
-	reps, _ := strconv.Atoi(repsStr)
+	startStr, endStr, isRange := strings.Cut(repsStr, "-")
+	reps, _ := strconv.Atoi(startStr)
+	rangeEnd, _ := strconv.Atoi(endStr)
 
 	// Create the appropriate number of sets
 	for i := 0; i < set.Sets; i++ {
@@ -74,7 +150,7 @@ func convertSets(set program.Set) []RoutineSet {
 
 		// Only add weight if it's specified (accessories have 0 weight)
 		if set.Weight > 0 {
-			weightKg := LbsToKg(set.Weight)
+			weightKg := ToKg(set.Weight, unit)
 			routineSet.WeightKg = &weightKg
 		}
 
@@ -89,23 +165,57 @@ func convertSets(set program.Set) []RoutineSet {
 					Start: &reps,
 					End:   &endReps,
 				}
+			} else if isRange && rangeEnd > reps {
+				routineSet.RepRange = &RepRange{
+					Start: &reps,
+					End:   &rangeEnd,
+				}
 			} else {
 				routineSet.Reps = &reps
 			}
 		}
 
+		if set.Duration > 0 {
+			duration := set.Duration
+			routineSet.DurationSeconds = &duration
+		}
+		if set.Distance > 0 {
+			distance := int(math.Round(set.Distance))
+			routineSet.DistanceMeters = &distance
+		}
+
 		sets = append(sets, routineSet)
 	}
 
 	return sets
 }
 
+// fitExerciseType clears the fields of a jump, throw or conditioning set
+// that the Hevy exercise type does not record, so a sprint logged against a
+// distance/duration exercise doesn't carry reps. Unknown types are left as-is.
+func fitExerciseType(set *RoutineSet, exerciseType string) {
+	switch exerciseType {
+	case "duration":
+		set.Reps, set.RepRange, set.WeightKg, set.DistanceMeters = nil, nil, nil, nil
+	case "weight_duration":
+		set.Reps, set.RepRange, set.DistanceMeters = nil, nil, nil
+	case "distance_duration":
+		set.Reps, set.RepRange, set.WeightKg = nil, nil, nil
+	case "short_distance_weight":
+		set.Reps, set.RepRange, set.DurationSeconds = nil, nil, nil
+	case "reps_only", "bodyweight_reps", "bodyweight_assisted_reps":
+		set.WeightKg, set.DistanceMeters, set.DurationSeconds = nil, nil, nil
+	case "weight_reps":
+		set.DistanceMeters, set.DurationSeconds = nil, nil
+	}
+}
+
 // ConvertProgramToRoutines converts an entire program to Hevy routines
 func ConvertProgramToRoutines(prog *program.Program, mapper *ExerciseMapper) ([]CreateRoutineRequest, error) {
 	var routines []CreateRoutineRequest
 
 	for _, day := range prog.Days {
-		routine, err := ConvertDayToRoutine(day, mapper)
+		routine, err := ConvertDayToRoutine(prog, day, mapper)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,186 @@
+package hevy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WorkoutSet is a set logged in a completed workout
+type WorkoutSet struct {
+	Index           int      `json:"index"`
+	Type            SetType  `json:"type"`
+	WeightKg        *float64 `json:"weight_kg"`
+	Reps            *int     `json:"reps"`
+	DistanceMeters  *int     `json:"distance_meters"`
+	DurationSeconds *int     `json:"duration_seconds"`
+	RPE             *float64 `json:"rpe"`
+}
+
+// WorkoutExercise is an exercise logged in a completed workout
+type WorkoutExercise struct {
+	Index              int          `json:"index"`
+	Title              string       `json:"title"`
+	Notes              string       `json:"notes"`
+	ExerciseTemplateID string       `json:"exercise_template_id"`
+	SupersetID         *int         `json:"superset_id"`
+	Sets               []WorkoutSet `json:"sets"`
+}
+
+// Workout is a completed workout from GET /workouts
+type Workout struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	RoutineID   string            `json:"routine_id"`
+	Description string            `json:"description"`
+	StartTime   time.Time         `json:"start_time"`
+	EndTime     time.Time         `json:"end_time"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Exercises   []WorkoutExercise `json:"exercises"`
+}
+
+// WorkoutsResponse is the response from GET /workouts
+type WorkoutsResponse struct {
+	Page      int       `json:"page"`
+	PageCount int       `json:"page_count"`
+	Workouts  []Workout `json:"workouts"`
+}
+
+// Workout event types
+const (
+	WorkoutEventUpdated = "updated"
+	WorkoutEventDeleted = "deleted"
+)
+
+// WorkoutEvent is a workout created, updated or deleted since a timestamp.
+// Updated events carry the workout; deleted events only its ID.
+type WorkoutEvent struct {
+	Type      string     `json:"type"`
+	Workout   *Workout   `json:"workout,omitempty"`
+	ID        string     `json:"id,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// WorkoutEventsResponse is the response from GET /workouts/events
+type WorkoutEventsResponse struct {
+	Page      int            `json:"page"`
+	PageCount int            `json:"page_count"`
+	Events    []WorkoutEvent `json:"events"`
+}
+
+// GetWorkouts fetches all completed workouts, newest first (paginated)
+func (c *Client) GetWorkouts() ([]Workout, error) {
+	var allWorkouts []Workout
+	page := 1
+
+	for {
+		var result WorkoutsResponse
+		pageURL := fmt.Sprintf("%s/workouts?page=%d&pageSize=10", baseURL, page)
+		if err := c.getPage(pageURL, "workouts", &result); err != nil {
+			return nil, err
+		}
+
+		allWorkouts = append(allWorkouts, result.Workouts...)
+
+		if page >= result.PageCount {
+			break
+		}
+		page++
+	}
+
+	return allWorkouts, nil
+}
+
+// GetWorkoutEvents fetches every workout update and deletion since the given
+// time (paginated), oldest first
+func (c *Client) GetWorkoutEvents(since time.Time) ([]WorkoutEvent, error) {
+	var allEvents []WorkoutEvent
+	sinceParam := url.QueryEscape(since.UTC().Format(time.RFC3339))
+	page := 1
+
+	for {
+		var result WorkoutEventsResponse
+		pageURL := fmt.Sprintf("%s/workouts/events?page=%d&pageSize=10&since=%s", baseURL, page, sinceParam)
+		if err := c.getPage(pageURL, "workout events", &result); err != nil {
+			return nil, err
+		}
+
+		allEvents = append(allEvents, result.Events...)
+
+		if page >= result.PageCount {
+			break
+		}
+		page++
+	}
+
+	return allEvents, nil
+}
+
+// getPage fetches one page of a paginated list into result, closing the
+// response body before it returns
+func (c *Client) getPage(pageURL, what string, result any) error {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("api-key", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// ApplyWorkoutEvents returns workouts with the events applied: updated
+// workouts replace (or are added to) the list and deleted ones are removed
+func ApplyWorkoutEvents(workouts []Workout, events []WorkoutEvent) []Workout {
+	byID := make(map[string]int, len(workouts))
+	merged := append([]Workout{}, workouts...)
+	for i, w := range merged {
+		byID[w.ID] = i
+	}
+
+	deleted := make(map[string]bool)
+	for _, event := range events {
+		switch event.Type {
+		case WorkoutEventUpdated:
+			if event.Workout == nil {
+				continue
+			}
+			delete(deleted, event.Workout.ID)
+			if i, ok := byID[event.Workout.ID]; ok {
+				merged[i] = *event.Workout
+			} else {
+				byID[event.Workout.ID] = len(merged)
+				merged = append(merged, *event.Workout)
+			}
+		case WorkoutEventDeleted:
+			deleted[event.ID] = true
+		}
+	}
+
+	kept := merged[:0]
+	for _, w := range merged {
+		if !deleted[w.ID] {
+			kept = append(kept, w)
+		}
+	}
+	return kept
+}
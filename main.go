@@ -1,8 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,32 +17,72 @@ import (
 )
 
 func main() {
+	// Subcommands run non-interactively; no arguments, or only flags, keeps
+	// the prompt flow
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	runInteractive(os.Args[1:])
+}
+
+// runInteractive drives the full prompt-based flow over stdin
+func runInteractive(args []string) {
+	fs := flag.NewFlagSet("lifting", flag.ExitOnError)
+	profile := fs.String("profile", "", "athlete profile to use; a new name is created when the config is saved")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fmt.Fprintln(fs.Output(), "\nInteractive flags:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
 	reader := prompt.NewReader()
 
+	// Load any exercise library before configs are validated against it
+	if err := loadLibrary(config.DefaultLibraryFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading exercise library: %v\n", err)
+		os.Exit(1)
+	}
+
+	path, newProfile, err := chooseMemory(reader, *profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error choosing profile: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Gather configuration, optionally using saved memory
-	snapshot, err := memory.Load(memory.DefaultFile)
+	snapshot, err := memory.Load(path)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load memory file: %v\n", err)
 	}
 
-	cfg, err := gatherConfig(reader, snapshot)
+	cfg, record, err := gatherConfig(reader, snapshot)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error gathering config: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Generate the program
-	prog := program.Generate(cfg)
+	prog, err := program.Generate(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating program: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Ask about Hevy upload
 	if reader.AskHevyUpload() {
-		if err := uploadToHevy(reader, prog); err != nil {
+		if err := uploadToHevy(reader.GetHevyAPIKey(), cfg, prog); err != nil {
 			fmt.Fprintf(os.Stderr, "Error uploading to Hevy: %v\n", err)
 			os.Exit(1)
 		}
 	} else {
 		// Export to CSV
-		filename := reader.GetOutputFilename()
+		filename := reader.GetOutputFilename(export.DefaultFilename(prog))
 		if err := export.ToCSV(prog, filename); err != nil {
 			fmt.Fprintf(os.Stderr, "Error exporting CSV: %v\n", err)
 			os.Exit(1)
@@ -49,47 +91,218 @@ func main() {
 	}
 
 	if reader.AskSaveMemory() {
-		if err := memory.Save(memory.DefaultFile, cfg); err != nil {
+		if newProfile != "" {
+			_, err = memory.CreateProfile(newProfile, cfg, memory.Record{Note: "profile created"})
+		} else {
+			err = memory.Append(path, cfg, record)
+		}
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to save memory: %v\n", err)
 		} else {
-			fmt.Printf("Saved program memory to %s\n", memory.DefaultFile)
+			fmt.Printf("Saved program memory to %s\n", path)
 		}
 	}
 
 	fmt.Println("\nHappy lifting!")
 }
 
-func gatherConfig(reader *prompt.Reader, snapshot *memory.Snapshot) (*config.Config, error) {
+// chooseMemory picks the memory file for the interactive flow: the named
+// profile, else one chosen at the prompt when profiles exist, else the shared
+// memory file. newProfile names a profile to create when the config is saved.
+func chooseMemory(reader *prompt.Reader, profile string) (path, newProfile string, err error) {
+	if profile == "" {
+		profiles, err := memory.Profiles()
+		if err != nil {
+			return "", "", err
+		}
+		if len(profiles) == 0 {
+			return memory.DefaultFile, "", nil
+		}
+		if profile = reader.ChooseProfile(profiles, memory.ValidateProfileName); profile == "" {
+			return memory.DefaultFile, "", nil
+		}
+	}
+
+	if path, err = memory.ProfilePath(profile); err != nil {
+		return "", "", err
+	}
+	exists, err := memory.ProfileExists(profile)
+	if err != nil {
+		return "", "", err
+	}
+	if !exists {
+		fmt.Printf("\nStarting new profile %s\n", profile)
+		return path, profile, nil
+	}
+	fmt.Printf("\nUsing profile %s\n", profile)
+	return path, "", nil
+}
+
+// loadLibrary makes the exercise library at path the active library,
+// keeping the built-in library if the file does not exist
+func loadLibrary(path string) error {
+	lib, err := config.LoadLibrary(path)
+	if err != nil {
+		return err
+	}
+	if lib != nil {
+		config.UseLibrary(lib)
+	}
+	return nil
+}
+
+// gatherConfig builds the config from the prompts or saved memory, along
+// with the history record to save it under
+func gatherConfig(reader *prompt.Reader, snapshot *memory.Snapshot) (*config.Config, memory.Record, error) {
 	if snapshot == nil {
-		return reader.GatherConfig()
+		cfg, err := reader.GatherConfig()
+		return cfg, memory.Record{}, err
 	}
 
 	fmt.Printf("\nFound saved configuration from %s\n", snapshot.SavedAt.Local().Format(time.RFC1123))
-	printTrainingMaxes(snapshot.Config.TrainingMaxes)
+	printTrainingMaxes(snapshot.Config)
 
-	switch reader.ChooseConfigStartMode() {
+	switch reader.ChooseConfigStartMode(snapshot.Config.Units) {
 	case prompt.ConfigStartReuseSaved:
 		fmt.Println("\nUsing saved configuration.")
-		return memory.CloneConfig(snapshot.Config), nil
+		return memory.CloneConfig(snapshot.Config), memory.Record{}, nil
 	case prompt.ConfigStartNextCycle:
 		fmt.Println("\nApplying standard 5/3/1 training max increases for next cycle...")
 		next := memory.NextCycleConfig(snapshot.Config)
-		printTrainingMaxes(next.TrainingMaxes)
-		return next, nil
+		record := memory.Record{Note: "next cycle"}
+		if snapshot.Config.HasTMTest() {
+			if reps := reader.GatherTMTestReps(snapshot.Config.LiftOrder); reps != nil {
+				var results []memory.TMTestResult
+				next, results = memory.NextCycleConfigAfterTMTest(snapshot.Config, reps)
+				printTMTestResults(next.Units, results)
+				for _, result := range results {
+					record.Results = append(record.Results, result.Log())
+				}
+			}
+		} else if reps, reset := reader.GatherAMRAPReps(snapshot.Config.LiftOrder); reps != nil {
+			prog, err := program.Generate(snapshot.Config)
+			if err != nil {
+				return nil, record, err
+			}
+			logs := amrapLogsFromReps(prog, reps)
+			rules := memory.NewProgressionRules()
+			rules.Reset = reset
+			progressed, results, err := memory.NextCycleConfigFromAMRAP(snapshot.Config, logs, rules)
+			if err != nil {
+				return nil, record, err
+			}
+			printProgression(progressed.Units, results)
+			if reader.ConfirmProgression() {
+				next = progressed
+			}
+			record.Results = logs
+		}
+		printTrainingMaxes(next)
+		printBBBChallenge(snapshot.Config, next)
+		return next, record, nil
 	default:
-		return reader.GatherConfig()
+		cfg, err := reader.GatherConfig()
+		return cfg, memory.Record{}, err
 	}
 }
 
-func printTrainingMaxes(maxes config.LiftMaxes) {
+func printTrainingMaxes(cfg *config.Config) {
 	fmt.Println("Training maxes:")
-	for _, lift := range config.AllLifts() {
-		fmt.Printf("  %s: %.0f lbs\n", lift, maxes[lift])
+	for _, lift := range cfg.Lifts() {
+		fmt.Printf("  %s: %s\n", lift, cfg.Units.Format(cfg.TrainingMaxes[lift]))
 	}
 }
 
-func uploadToHevy(reader *prompt.Reader, prog *program.Program) error {
-	apiKey := reader.GetHevyAPIKey()
+// printTMTestResults reports the training max decision for each tested lift
+func printTMTestResults(unit config.Unit, results []memory.TMTestResult) {
+	fmt.Println("7th-week TM test:")
+	for _, result := range results {
+		if result.Passed {
+			fmt.Printf("  %s: %d reps at %s - passed, standard increase\n", result.Lift, result.Reps, unit.Format(result.TestedMax))
+		} else {
+			fmt.Printf("  %s: %d reps at %s - missed %d, TM reset to %s\n", result.Lift, result.Reps, unit.Format(result.TestedMax), config.TMTestReps, unit.Format(result.TrainingMax))
+		}
+	}
+}
+
+// amrapLogsFromHevy converts AMRAP results imported from Hevy into logs for
+// progression, judging each set by the weight actually lifted
+func amrapLogsFromHevy(results []hevy.AMRAPResult) []memory.AMRAPLog {
+	logs := make([]memory.AMRAPLog, 0, len(results))
+	for _, r := range results {
+		weight := r.Weight
+		if r.LoggedWeight > 0 {
+			weight = r.LoggedWeight
+		}
+		minReps, _ := strconv.Atoi(strings.TrimSuffix(r.Prescribed, "+"))
+		logs = append(logs, memory.AMRAPLog{
+			Lift:       r.Lift,
+			Cycle:      r.Cycle,
+			Percentage: r.Percentage,
+			Weight:     weight,
+			MinReps:    minReps,
+			Reps:       r.Reps,
+		})
+	}
+	return logs
+}
+
+// amrapLogsFromReps turns reps entered by hand into logs for progression.
+// The reps are for each lift's heaviest AMRAP set (the 1+ set) in the last
+// cycle of the program that has one.
+func amrapLogsFromReps(prog *program.Program, reps map[config.Lift]int) []memory.AMRAPLog {
+	heaviest := make(map[config.Lift]memory.AMRAPLog)
+	for _, day := range prog.Days {
+		for _, set := range day.Sets {
+			lift := config.Lift(set.Exercise)
+			made, ok := reps[lift]
+			if !ok || set.Kind != program.SetAMRAP {
+				continue
+			}
+			if current, seen := heaviest[lift]; seen && (day.Cycle < current.Cycle || (day.Cycle == current.Cycle && set.Percentage < current.Percentage)) {
+				continue
+			}
+			minReps, _ := strconv.Atoi(strings.TrimSuffix(set.Reps, "+"))
+			heaviest[lift] = memory.AMRAPLog{
+				Lift:       lift,
+				Cycle:      day.Cycle,
+				Percentage: set.Percentage,
+				Weight:     set.Weight,
+				MinReps:    minReps,
+				Reps:       made,
+			}
+		}
+	}
+
+	logs := make([]memory.AMRAPLog, 0, len(heaviest))
+	for _, log := range heaviest {
+		logs = append(logs, log)
+	}
+	return logs
+}
+
+// printProgression reports the training max decision and reason for each lift
+func printProgression(unit config.Unit, results []memory.ProgressionResult) {
+	fmt.Println("Training max progression:")
+	for _, result := range results {
+		fmt.Printf("  %s: %s -> %s (%s) - %s\n", result.Lift, unit.Format(result.Previous), unit.Format(result.TrainingMax), result.Decision, result.Reason)
+	}
+}
+
+// printBBBChallenge reports BBB challenge progress after moving to the next cycle
+func printBBBChallenge(prev, next *config.Config) {
+	switch {
+	case next.BBBChallenge != nil:
+		challenge := next.BBBChallenge
+		fmt.Printf("BBB challenge: month %d of %d (%.0f%%)\n", challenge.Month, len(challenge.Percentages), challenge.Current())
+	case prev.BBBChallenge != nil:
+		fmt.Printf("BBB challenge complete! Back to %.0f%% BBB.\n", next.BBBPercentage)
+	}
+}
+
+// uploadToHevy syncs a program generated from cfg to Hevy, so what is
+// uploaded matches what was shown or saved
+func uploadToHevy(apiKey string, cfg *config.Config, prog *program.Program) error {
 	client := hevy.NewClient(apiKey)
 
 	// Fetch exercise templates
@@ -102,6 +315,7 @@ func uploadToHevy(reader *prompt.Reader, prog *program.Program) error {
 
 	// Create mapper
 	mapper := hevy.NewExerciseMapper(templates)
+	mapper.AddCustomLifts(cfg)
 
 	// Convert program to Hevy routines
 	fmt.Println("\nConverting program to Hevy routines...")
@@ -131,20 +345,23 @@ func uploadToHevy(reader *prompt.Reader, prog *program.Program) error {
 		routineByTitle[r.Title] = r.ID
 	}
 
-	// Get or create folders for each week
-	fmt.Println("\nSetting up weekly folders...")
-	weekFolders := make(map[int]int) // week number -> folder ID
-	for week := 1; week <= 4; week++ {
-		folderName := fmt.Sprintf("531 BBB Week %d", week)
+	// Get or create folders (one per week, or one per cycle for blocks)
+	fmt.Println("\nSetting up routine folders...")
+	folders := make(map[string]int) // folder title -> folder ID
+	for _, day := range prog.Days {
+		folderName := prog.FolderTitle(day)
+		if _, done := folders[folderName]; done {
+			continue
+		}
 		if folderID, exists := folderByName[folderName]; exists {
-			weekFolders[week] = folderID
+			folders[folderName] = folderID
 			fmt.Printf("  Found existing folder: %s\n", folderName)
 		} else {
 			folder, err := client.CreateFolder(folderName)
 			if err != nil {
 				return fmt.Errorf("failed to create folder %s: %w", folderName, err)
 			}
-			weekFolders[week] = folder.ID
+			folders[folderName] = folder.ID
 			fmt.Printf("  Created folder: %s\n", folderName)
 		}
 	}
@@ -153,9 +370,8 @@ func uploadToHevy(reader *prompt.Reader, prog *program.Program) error {
 	fmt.Printf("\nSyncing %d routines to Hevy...\n", len(routines))
 	created, updated := 0, 0
 	for i, routine := range routines {
-		// Determine week from routine index (4 routines per week)
-		week := (i / 4) + 1
-		folderID := weekFolders[week]
+		// Routines are converted in program order, one per day
+		folderID := folders[prog.FolderTitle(prog.Days[i])]
 		routine.FolderID = &folderID
 
 		var err error
@@ -207,6 +423,51 @@ func uploadToHevy(reader *prompt.Reader, prog *program.Program) error {
 	return nil
 }
 
+// importAMRAPResults fetches the workouts logged in Hevy, or only those
+// changed since a time when it is non-zero, and matches them to the
+// program's AMRAP sets
+func importAMRAPResults(apiKey string, cfg *config.Config, prog *program.Program, since time.Time) ([]hevy.AMRAPResult, error) {
+	client := hevy.NewClient(apiKey)
+
+	fmt.Println("\nFetching exercise templates from Hevy...")
+	templates, err := client.GetExerciseTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exercise templates: %w", err)
+	}
+	mapper := hevy.NewExerciseMapper(templates)
+	mapper.AddCustomLifts(cfg)
+
+	var workouts []hevy.Workout
+	if since.IsZero() {
+		fmt.Println("Fetching logged workouts...")
+		if workouts, err = client.GetWorkouts(); err != nil {
+			return nil, fmt.Errorf("failed to fetch workouts: %w", err)
+		}
+	} else {
+		fmt.Printf("Fetching workouts changed since %s...\n", since.Local().Format(time.RFC1123))
+		events, err := client.GetWorkoutEvents(since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch workout events: %w", err)
+		}
+		workouts = hevy.ApplyWorkoutEvents(nil, events)
+	}
+	fmt.Printf("Found %d workouts\n", len(workouts))
+
+	return hevy.MatchAMRAPResults(prog, workouts, mapper)
+}
+
+// printAMRAPResults lists the reps achieved on each AMRAP set
+func printAMRAPResults(unit config.Unit, results []hevy.AMRAPResult) {
+	if len(results) == 0 {
+		fmt.Println("\nNo logged AMRAP sets matched the program.")
+		return
+	}
+	fmt.Println("\nAMRAP results:")
+	for _, r := range results {
+		fmt.Printf("  C%dW%dD%d %s: %s x%s (%.0f%%) - %d reps\n", r.Cycle, r.Week, r.DayNum, r.Lift, unit.Format(r.Weight), r.Prescribed, r.Percentage, r.Reps)
+	}
+}
+
 // isRateLimitError checks if an error is a rate limit error (429)
 func isRateLimitError(err error) bool {
 	if err == nil {
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"lifting/config"
+	"lifting/program"
+)
+
+// Entry is one saved cycle in the training history. Entries are only ever
+// appended; a rollback appends a copy of an earlier entry's config.
+type Entry struct {
+	ID          int            `json:"id"` // sequential, starting at 1
+	SavedAt     time.Time      `json:"saved_at"`
+	Note        string         `json:"note,omitempty"` // why it was saved, e.g. "next cycle"
+	Config      *config.Config `json:"config"`
+	ProgramHash string         `json:"program_hash,omitempty"` // ProgramHash of the config's program
+	TMChanges   []TMChange     `json:"tm_changes,omitempty"`   // changes from the previous entry
+
+	// AMRAP sets logged in the previous cycle that this entry's training
+	// maxes were based on
+	Results []AMRAPLog `json:"results,omitempty"`
+}
+
+// TMChange is a training max that changed between two history entries
+type TMChange struct {
+	Lift config.Lift `json:"lift"`
+	From float64     `json:"from"`
+	To   float64     `json:"to"`
+}
+
+// Record describes a save for its history entry
+type Record struct {
+	Note    string
+	Results []AMRAPLog
+}
+
+// ProgramHash returns a short fingerprint of the program a config generates,
+// so entries that would produce the same training can be recognised. It is
+// empty if the config does not generate.
+func ProgramHash(cfg *config.Config) string {
+	prog, err := program.Generate(cfg)
+	if err != nil {
+		return ""
+	}
+	data, err := json.Marshal(prog)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Append saves cfg as the current config and appends it to the memory
+// file's history. Saving a config identical to the latest entry only updates
+// the saved time.
+func Append(path string, cfg *config.Config, record Record) error {
+	if cfg == nil {
+		return fmt.Errorf("cannot save nil config")
+	}
+
+	snapshot, err := Load(path)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		snapshot = &Snapshot{}
+	}
+
+	now := time.Now().UTC()
+	snapshot.SavedAt = now
+	snapshot.Config = CloneConfig(cfg)
+
+	var previous *Entry
+	if n := len(snapshot.History); n > 0 {
+		previous = &snapshot.History[n-1]
+	}
+	if previous == nil || !sameConfig(previous.Config, cfg) || len(record.Results) > 0 {
+		snapshot.History = append(snapshot.History, newEntry(len(snapshot.History)+1, now, cfg, previous, record))
+	}
+
+	return write(path, snapshot)
+}
+
+// newEntry builds a history entry for cfg, recording its training max
+// changes from the previous entry
+func newEntry(id int, savedAt time.Time, cfg *config.Config, previous *Entry, record Record) Entry {
+	entry := Entry{
+		ID:          id,
+		SavedAt:     savedAt,
+		Note:        record.Note,
+		Config:      CloneConfig(cfg),
+		ProgramHash: ProgramHash(cfg),
+		Results:     record.Results,
+	}
+	if previous != nil {
+		entry.TMChanges = TMChanges(previous.Config, cfg)
+	}
+	return entry
+}
+
+// Entry returns the history entry with the given ID
+func (s *Snapshot) Entry(id int) (*Entry, error) {
+	for i := range s.History {
+		if s.History[i].ID == id {
+			return &s.History[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no history entry %d (have %d)", id, len(s.History))
+}
+
+// Rollback makes the config of history entry id current again, appending it
+// to the history as a new entry, and returns the new entry
+func Rollback(path string, id int) (*Entry, error) {
+	snapshot, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, fmt.Errorf("no saved configuration found at %s", path)
+	}
+	target, err := snapshot.Entry(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Append(path, target.Config, Record{Note: fmt.Sprintf("rollback to #%d", id)}); err != nil {
+		return nil, err
+	}
+	snapshot, err = Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot.History[len(snapshot.History)-1], nil
+}
+
+// TMChanges lists the training maxes that differ between two configs, in
+// the lift order of to
+func TMChanges(from, to *config.Config) []TMChange {
+	var changes []TMChange
+	for _, lift := range to.Lifts() {
+		before, after := from.TrainingMaxes[lift], to.TrainingMaxes[lift]
+		if before != after {
+			changes = append(changes, TMChange{Lift: lift, From: before, To: after})
+		}
+	}
+	return changes
+}
+
+// FieldChange is a config field that differs between two configs. Values are
+// JSON-encoded, and empty when the field is unset on that side.
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// DiffConfigs lists every config field that differs between two configs,
+// sorted by field path (e.g. "TrainingMaxes.Squat")
+func DiffConfigs(from, to *config.Config) ([]FieldChange, error) {
+	before, err := flattenConfig(from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := flattenConfig(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	for field, value := range before {
+		if after[field] != value {
+			changes = append(changes, FieldChange{Field: field, From: value, To: after[field]})
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, To: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// flattenConfig maps each leaf of a config's JSON form to its encoded value
+func flattenConfig(cfg *config.Config) (map[string]string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	fields := make(map[string]string)
+	var walk func(path string, node any)
+	walk = func(path string, node any) {
+		switch v := node.(type) {
+		case map[string]any:
+			for key, child := range v {
+				if path != "" {
+					key = path + "." + key
+				}
+				walk(key, child)
+			}
+		case []any:
+			for i, child := range v {
+				walk(fmt.Sprintf("%s[%d]", path, i), child)
+			}
+		case nil:
+		default:
+			encoded, _ := json.Marshal(v)
+			fields[path] = string(encoded)
+		}
+	}
+	walk("", tree)
+	return fields, nil
+}
+
+// sameConfig reports whether two configs save identically
+func sameConfig(a, b *config.Config) bool {
+	before, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	after, err := json.Marshal(b)
+	return err == nil && bytes.Equal(before, after)
+}
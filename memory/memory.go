@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,10 +13,13 @@ import (
 
 const DefaultFile = ".531bbb_memory.json"
 
-// Snapshot stores the last saved config and timestamp.
+// Snapshot stores the last saved config and timestamp, and the history of
+// every config saved before it.
 type Snapshot struct {
+	Version int            `json:"version"` // schema version, see SchemaVersion
 	SavedAt time.Time      `json:"saved_at"`
 	Config  *config.Config `json:"config"`
+	History []Entry        `json:"history,omitempty"`
 }
 
 // Load reads memory from disk. If no file exists, it returns (nil, nil).
@@ -28,28 +32,60 @@ func Load(path string) (*Snapshot, error) {
 		return nil, fmt.Errorf("failed to read memory file: %w", err)
 	}
 
-	var snapshot Snapshot
-	if err := json.Unmarshal(data, &snapshot); err != nil {
-		return nil, fmt.Errorf("failed to parse memory file: %w", err)
+	snapshot, err := decode(data)
+	if err != nil {
+		return nil, err
 	}
 	if snapshot.Config == nil {
 		return nil, fmt.Errorf("memory file is missing config")
 	}
+	snapshot.Config.ApplyDefaults()
+	if err := snapshot.Config.Validate(); err != nil {
+		return nil, fmt.Errorf("memory file has invalid config:\n%w", err)
+	}
+	for _, entry := range snapshot.History {
+		if entry.Config == nil {
+			return nil, fmt.Errorf("memory file history entry %d is missing config", entry.ID)
+		}
+		entry.Config.ApplyDefaults()
+	}
 
-	return &snapshot, nil
+	return snapshot, nil
 }
 
-// Save writes config memory to disk.
-func Save(path string, cfg *config.Config) error {
-	if cfg == nil {
-		return fmt.Errorf("cannot save nil config")
+// decode parses memory file contents, upgrading files written with an older
+// schema. Fields the current schema doesn't know are rejected rather than
+// silently dropped.
+func decode(data []byte) (*Snapshot, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse memory file: %w", err)
+	}
+	if _, err := migrate(doc); err != nil {
+		return nil, err
 	}
 
-	snapshot := Snapshot{
-		SavedAt: time.Now().UTC(),
-		Config:  CloneConfig(cfg),
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode migrated memory file: %w", err)
 	}
+	var snapshot Snapshot
+	dec := json.NewDecoder(bytes.NewReader(migrated))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse memory file: %w", err)
+	}
+	return &snapshot, nil
+}
 
+// Save writes config memory to disk, appending it to the history.
+func Save(path string, cfg *config.Config) error {
+	return Append(path, cfg, Record{})
+}
+
+// write encodes a snapshot to disk with the current schema version
+func write(path string, snapshot *Snapshot) error {
+	snapshot.Version = SchemaVersion
 	data, err := json.MarshalIndent(snapshot, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to encode memory file: %w", err)
@@ -65,42 +101,92 @@ func Save(path string, cfg *config.Config) error {
 
 // CloneConfig creates a deep copy of config.
 func CloneConfig(cfg *config.Config) *config.Config {
-	if cfg == nil {
-		return nil
-	}
+	return cfg.Clone()
+}
 
-	cloned := &config.Config{
-		TrainingMaxes: make(config.LiftMaxes, len(cfg.TrainingMaxes)),
-		LiftOrder:     append([]config.Lift{}, cfg.LiftOrder...),
-		BBBPercentage: cfg.BBBPercentage,
-		BBBPairing:    make(map[config.Lift]config.Lift, len(cfg.BBBPairing)),
-		Accessories:   make(map[config.Lift]string, len(cfg.Accessories)),
+// NextCycleConfig returns a copy with standard 5/3/1 TM increases applied.
+// An active BBB challenge moves on to its next month, and ends after the last.
+// A block config advances once for every leader and anchor cycle in the block.
+func NextCycleConfig(cfg *config.Config) *config.Config {
+	next := cfg.Clone()
+	if next == nil {
+		return nil
 	}
 
-	for lift, max := range cfg.TrainingMaxes {
-		cloned.TrainingMaxes[lift] = max
+	cycles := 1
+	if next.Block != nil {
+		cycles = next.Block.LeaderCycles + next.Block.AnchorCycles
 	}
-	for lift, paired := range cfg.BBBPairing {
-		cloned.BBBPairing[lift] = paired
-	}
-	for lift, accessory := range cfg.Accessories {
-		cloned.Accessories[lift] = accessory
+	for i := 0; i < cycles; i++ {
+		next.AdvanceCycle()
 	}
 
-	return cloned
+	return next
 }
 
-// NextCycleConfig returns a copy with standard 5/3/1 TM increases applied.
-func NextCycleConfig(cfg *config.Config) *config.Config {
-	next := CloneConfig(cfg)
+// TMTestResult is the outcome of a 7th-week TM test for one lift
+type TMTestResult struct {
+	Lift        config.Lift
+	Reps        int     // reps made at 100% of the tested training max
+	TestedMax   float64 // training max the test was run with
+	Passed      bool
+	TrainingMax float64 // training max for the next cycle
+}
+
+// Log returns the test as a logged set for the history: reps at 100% of
+// the tested training max against a TMTestReps minimum
+func (r TMTestResult) Log() AMRAPLog {
+	return AMRAPLog{
+		Lift:       r.Lift,
+		Percentage: 100,
+		Weight:     r.TestedMax,
+		MinReps:    config.TMTestReps,
+		Reps:       r.Reps,
+	}
+}
+
+// NextCycleConfigAfterTMTest is NextCycleConfig with 7th-week TM test
+// results applied. A lift that made config.TMTestReps reps keeps the standard
+// progression; one that fell short restarts at 90% of the training max it was
+// tested with, plus the increases of any cycles run after the test (a block's
+// anchor cycles). Lifts without a result are not changed.
+func NextCycleConfigAfterTMTest(cfg *config.Config, reps map[config.Lift]int) (*config.Config, []TMTestResult) {
+	next := NextCycleConfig(cfg)
 	if next == nil {
-		return nil
+		return nil, nil
+	}
+	tested := cfg.TMTestConfig()
+	reset := tested.Clone()
+
+	var results []TMTestResult
+	for _, lift := range cfg.Lifts() {
+		made, ok := reps[lift]
+		if !ok {
+			continue
+		}
+		result := TMTestResult{
+			Lift:      lift,
+			Reps:      made,
+			TestedMax: tested.TrainingMaxes[lift],
+			Passed:    made >= config.TMTestReps,
+		}
+		if !result.Passed {
+			reset.TrainingMaxes[lift] = result.TestedMax * config.TMTestReduction
+		}
+		results = append(results, result)
 	}
 
-	next.TrainingMaxes[config.Squat] += 10
-	next.TrainingMaxes[config.Deadlift] += 10
-	next.TrainingMaxes[config.Bench] += 5
-	next.TrainingMaxes[config.OHP] += 5
+	if reset.Block != nil {
+		for i := 0; i < reset.Block.AnchorCycles; i++ {
+			reset.AdvanceCycle()
+		}
+	}
+	for i, result := range results {
+		if !result.Passed {
+			next.TrainingMaxes[result.Lift] = reset.TrainingMaxes[result.Lift]
+		}
+		results[i].TrainingMax = next.TrainingMaxes[result.Lift]
+	}
 
-	return next
+	return next, results
 }
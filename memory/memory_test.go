@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"lifting/config"
+)
+
+// TestSaveKeepsEveryField checks a config survives a save and load, using
+// the config package's fixture that sets every field a valid config can
+func TestSaveKeepsEveryField(t *testing.T) {
+	cfg, err := config.LoadFile(filepath.Join("..", "config", "testdata", "full_config.json"))
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	snapshot, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(snapshot.Config, cfg) {
+		t.Errorf("loaded config = %+v, want %+v", snapshot.Config, cfg)
+	}
+	if !reflect.DeepEqual(snapshot.History[0].Config, cfg) {
+		t.Errorf("history config = %+v, want %+v", snapshot.History[0].Config, cfg)
+	}
+}
+
+// TestNextCycleKeepsWarmupOnDeload is a regression test for the next cycle
+// losing the deload warm-up setting, which it copies through Clone
+func TestNextCycleKeepsWarmupOnDeload(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.TrainingMaxes = config.LiftMaxes{config.Squat: 315, config.Bench: 225, config.Deadlift: 405, config.OHP: 135}
+	cfg.WarmupOnDeload = true
+
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := Save(path, NextCycleConfig(cfg)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	snapshot, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !snapshot.Config.WarmupOnDeload {
+		t.Error("the saved next cycle lost WarmupOnDeload")
+	}
+}
+
+// TestNextCycleConfigAfterTMTest checks a failed TM test resets the training
+// max it was run with, and a block's anchor cycles still add their increases
+func TestNextCycleConfigAfterTMTest(t *testing.T) {
+	tests := []struct {
+		name         string
+		block        *config.Block
+		squat, bench float64 // next training maxes after a passed squat and failed bench test
+	}{
+		// Tested at 300/200; bench resets to 180
+		{name: "single cycle", squat: 310, bench: 180},
+		// Tested at 320/210 after two leaders; bench resets to 189, plus one anchor cycle
+		{
+			name:  "block",
+			block: &config.Block{LeaderTemplate: "bbb", LeaderCycles: 2, SeventhWeek: config.SeventhWeekTMTest, AnchorTemplate: "fsl", AnchorCycles: 1},
+			squat: 330, bench: 194,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cfg.TrainingMaxes = config.LiftMaxes{config.Squat: 300, config.Bench: 200, config.Deadlift: 400, config.OHP: 130}
+			cfg.Block = tt.block
+			if tt.block == nil {
+				cfg.SeventhWeek = config.SeventhWeekTMTest
+			}
+
+			next, results := NextCycleConfigAfterTMTest(cfg, map[config.Lift]int{config.Squat: 6, config.Bench: 3})
+			if got := next.TrainingMaxes[config.Squat]; got != tt.squat {
+				t.Errorf("Squat TM = %g, want %g", got, tt.squat)
+			}
+			if got := next.TrainingMaxes[config.Bench]; got != tt.bench {
+				t.Errorf("Bench TM = %g, want %g", got, tt.bench)
+			}
+			if len(results) != 2 || !results[0].Passed || results[1].Passed {
+				t.Fatalf("results = %+v, want a passed squat and a failed bench", results)
+			}
+			if results[1].TrainingMax != tt.bench {
+				t.Errorf("bench result TrainingMax = %g, want %g", results[1].TrainingMax, tt.bench)
+			}
+		})
+	}
+}
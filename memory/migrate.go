@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is the memory file schema written by Save.
+//
+//	1: {"saved_at", "config"} with only the latest config. The earliest
+//	   files have no config Units; they were always pounds.
+//	2: adds "history", the append-only list of saved cycles, and "version"
+const SchemaVersion = 2
+
+// migration upgrades a decoded memory file by one schema version
+type migration func(doc map[string]json.RawMessage) error
+
+// migrations holds the upgrade from each version to the next, keyed by the
+// version it upgrades from
+var migrations = map[int]migration{
+	1: migrateV1ToV2,
+}
+
+// migrate upgrades a decoded memory file to SchemaVersion in place and
+// returns the version it was written with
+func migrate(doc map[string]json.RawMessage) (int, error) {
+	version, err := detectVersion(doc)
+	if err != nil {
+		return 0, err
+	}
+	if version > SchemaVersion {
+		return 0, fmt.Errorf("memory file version %d is newer than this program supports (%d)", version, SchemaVersion)
+	}
+
+	for v := version; v < SchemaVersion; v++ {
+		step, ok := migrations[v]
+		if !ok {
+			return 0, fmt.Errorf("no migration from memory file version %d", v)
+		}
+		if err := step(doc); err != nil {
+			return 0, fmt.Errorf("failed to migrate memory file from version %d: %w", v, err)
+		}
+	}
+
+	doc["version"] = json.RawMessage(fmt.Sprint(SchemaVersion))
+	return version, nil
+}
+
+// detectVersion returns a memory file's schema version. Files written before
+// versioning are version 1, or 2 if they already keep a history.
+func detectVersion(doc map[string]json.RawMessage) (int, error) {
+	raw, ok := doc["version"]
+	if !ok {
+		if _, hasHistory := doc["history"]; hasHistory {
+			return 2, nil
+		}
+		return 1, nil
+	}
+
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid memory file version %s", raw)
+	}
+	return version, nil
+}
+
+// migrateV1ToV2 pins the units of configs saved before units existed to
+// pounds, and starts the history with the saved config
+func migrateV1ToV2(doc map[string]json.RawMessage) error {
+	raw, ok := doc["config"]
+	if !ok {
+		return fmt.Errorf("missing config")
+	}
+
+	var cfg map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return fmt.Errorf("failed to decode config: %w", err)
+	}
+	if _, ok := cfg["Units"]; !ok {
+		cfg["Units"] = json.RawMessage(`"lbs"`)
+	}
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	doc["config"] = raw
+
+	entry := map[string]json.RawMessage{
+		"id":     json.RawMessage("1"),
+		"note":   json.RawMessage(`"saved before history"`),
+		"config": raw,
+	}
+	if savedAt, ok := doc["saved_at"]; ok {
+		entry["saved_at"] = savedAt
+	}
+	history, err := json.Marshal([]map[string]json.RawMessage{entry})
+	if err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+	doc["history"] = history
+	return nil
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"lifting/config"
+)
+
+// TestLoadFixtures loads a memory file written by each historical schema
+// version and checks it is upgraded to the current schema
+func TestLoadFixtures(t *testing.T) {
+	tests := []struct {
+		file         string
+		units        config.Unit
+		template     string
+		squat        float64
+		cycle        int
+		historyLen   int
+		firstNote    string
+		latestResult bool
+	}{
+		{file: "v1_baseline.json", units: config.Pounds, template: "bbb", squat: 315, cycle: 1, historyLen: 1, firstNote: "saved before history"},
+		{file: "v1_kg.json", units: config.Kilograms, template: "fsl", squat: 140, cycle: 4, historyLen: 1, firstNote: "saved before history"},
+		{file: "v2_unversioned.json", units: config.Pounds, template: "bbb", squat: 325, cycle: 2, historyLen: 2},
+		{file: "v2.json", units: config.Pounds, template: "bbb", squat: 325, cycle: 2, historyLen: 2, latestResult: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			snapshot, err := Load(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			cfg := snapshot.Config
+			if cfg.Units != tt.units {
+				t.Errorf("Units = %q, want %q", cfg.Units, tt.units)
+			}
+			if cfg.Template != tt.template {
+				t.Errorf("Template = %q, want %q", cfg.Template, tt.template)
+			}
+			if got := cfg.TrainingMaxes[config.Squat]; got != tt.squat {
+				t.Errorf("Squat TM = %g, want %g", got, tt.squat)
+			}
+			if cfg.Cycle != tt.cycle {
+				t.Errorf("Cycle = %d, want %d", cfg.Cycle, tt.cycle)
+			}
+			if snapshot.SavedAt.IsZero() {
+				t.Error("SavedAt is zero")
+			}
+
+			if len(snapshot.History) != tt.historyLen {
+				t.Fatalf("history has %d entries, want %d", len(snapshot.History), tt.historyLen)
+			}
+			first := snapshot.History[0]
+			if first.ID != 1 || first.Note != tt.firstNote || first.Config == nil {
+				t.Errorf("first entry = #%d %q (config %v), want #1 %q", first.ID, first.Note, first.Config != nil, tt.firstNote)
+			}
+			latest := snapshot.History[len(snapshot.History)-1]
+			if got := latest.Config.TrainingMaxes[config.Squat]; got != tt.squat {
+				t.Errorf("latest entry Squat TM = %g, want %g", got, tt.squat)
+			}
+			if got := len(latest.Results) > 0; got != tt.latestResult {
+				t.Errorf("latest entry has results = %v, want %v", got, tt.latestResult)
+			}
+		})
+	}
+}
+
+// TestLoadBaselineKeepsPairing checks the v1 migration keeps every field the
+// original single-snapshot format saved
+func TestLoadBaselineKeepsPairing(t *testing.T) {
+	snapshot, err := Load(filepath.Join("testdata", "v1_baseline.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cfg := snapshot.Config
+	if got := cfg.BBBPairing[config.Squat]; got != config.Deadlift {
+		t.Errorf("Squat BBB pairing = %q, want %q", got, config.Deadlift)
+	}
+	if got := cfg.Accessories[config.Bench]; got != "Dips" {
+		t.Errorf("Bench accessory = %q, want %q", got, "Dips")
+	}
+	want := time.Date(2025, 1, 6, 18, 30, 0, 0, time.UTC)
+	if !snapshot.History[0].SavedAt.Equal(want) {
+		t.Errorf("first entry saved at %v, want %v", snapshot.History[0].SavedAt, want)
+	}
+}
+
+// TestLoadRejects checks files the current schema can't read are refused
+// instead of losing data
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: "future_version.json", want: "newer than this program supports"},
+		{file: "unknown_field.json", want: "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			_, err := Load(filepath.Join("testdata", tt.file))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Load error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestSaveUpgradesFile checks saving over an old file writes the current
+// schema and keeps its history
+func TestSaveUpgradesFile(t *testing.T) {
+	old, err := Load(filepath.Join("testdata", "v1_baseline.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := write(path, old); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := Save(path, NextCycleConfig(old.Config)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	saved, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load saved: %v", err)
+	}
+	if saved.Version != SchemaVersion {
+		t.Errorf("Version = %d, want %d", saved.Version, SchemaVersion)
+	}
+	if len(saved.History) != 2 {
+		t.Fatalf("history has %d entries, want 2", len(saved.History))
+	}
+	if got := saved.History[1].TMChanges; len(got) != 4 {
+		t.Errorf("latest entry has %d TM changes, want 4", len(got))
+	}
+}
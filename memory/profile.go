@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"lifting/config"
+)
+
+// ProfileAppDir is the directory under the user config directory
+// (os.UserConfigDir) that holds the profiles directory
+const ProfileAppDir = "lifting"
+
+// profileExt is the file extension of a profile's memory file
+const profileExt = ".json"
+
+// ProfileDir returns the directory holding one memory file per profile,
+// under the user config directory (os.UserConfigDir)
+func ProfileDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user config directory: %w", err)
+	}
+	return filepath.Join(dir, ProfileAppDir, "profiles"), nil
+}
+
+// ValidateProfileName checks a profile name is safe to use as a file name:
+// letters, digits, '-', '_' and '.', not starting with '.'
+func ValidateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name is empty")
+	}
+	if strings.HasPrefix(name, ".") {
+		return fmt.Errorf("profile name %q cannot start with '.'", name)
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.':
+		default:
+			return fmt.Errorf("profile name %q can only contain letters, digits, '-', '_' and '.'", name)
+		}
+	}
+	return nil
+}
+
+// ProfilePath returns the memory file of the named profile, which may not
+// exist yet
+func ProfilePath(name string) (string, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	dir, err := ProfileDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+profileExt), nil
+}
+
+// ProfileExists reports whether the named profile has a memory file
+func ProfileExists(name string) (bool, error) {
+	path, err := ProfilePath(name)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check profile %q: %w", name, err)
+	}
+	return true, nil
+}
+
+// Profiles returns the names of every saved profile, sorted
+func Profiles() ([]string, error) {
+	dir, err := ProfileDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles directory: %w", err)
+	}
+
+	var names []string
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), profileExt)
+		if !ok || file.IsDir() || ValidateProfileName(name) != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CreateProfile starts a new profile with cfg as its first history entry.
+// It fails if the profile already exists.
+func CreateProfile(name string, cfg *config.Config, record Record) (string, error) {
+	path, err := ProfilePath(name)
+	if err != nil {
+		return "", err
+	}
+	exists, err := ProfileExists(name)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "", fmt.Errorf("profile %q already exists", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create profiles directory: %w", err)
+	}
+	if err := Append(path, cfg, record); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// DeleteProfile removes a profile along with its config and history
+func DeleteProfile(name string) error {
+	path, err := ProfilePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("profile %q does not exist", name)
+		}
+		return fmt.Errorf("failed to delete profile %q: %w", name, err)
+	}
+	return nil
+}
+
+// RenameProfile renames a profile, keeping its config and history. It fails
+// rather than overwrite an existing profile.
+func RenameProfile(from, to string) error {
+	fromPath, err := ProfilePath(from)
+	if err != nil {
+		return err
+	}
+	toPath, err := ProfilePath(to)
+	if err != nil {
+		return err
+	}
+	exists, err := ProfileExists(from)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("profile %q does not exist", from)
+	}
+	if exists, err = ProfileExists(to); err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("profile %q already exists", to)
+	}
+	if err := os.Rename(fromPath, toPath); err != nil {
+		return fmt.Errorf("failed to rename profile %q: %w", from, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"lifting/config"
+)
+
+// TestProfiles checks profiles are kept apart under the user config
+// directory and can be renamed and deleted
+func TestProfiles(t *testing.T) {
+	// os.UserConfigDir reads one of these depending on the platform
+	for _, env := range []string{"XDG_CONFIG_HOME", "HOME", "AppData"} {
+		t.Setenv(env, t.TempDir())
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatalf("UserConfigDir: %v", err)
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.TrainingMaxes = map[config.Lift]float64{
+		config.Squat: 315, config.Bench: 225, config.Deadlift: 405, config.OHP: 135,
+	}
+	path, err := CreateProfile("alice", cfg, Record{Note: "profile created"})
+	if err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+	if want := filepath.Join(dir, ProfileAppDir, "profiles", "alice.json"); path != want {
+		t.Errorf("profile path = %s, want %s", path, want)
+	}
+	if _, err := CreateProfile("alice", cfg, Record{}); err == nil {
+		t.Error("CreateProfile over an existing profile succeeded")
+	}
+	if _, err := CreateProfile("bob", NextCycleConfig(cfg), Record{}); err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+
+	names, err := Profiles()
+	if err != nil {
+		t.Fatalf("Profiles: %v", err)
+	}
+	if !slices.Equal(names, []string{"alice", "bob"}) {
+		t.Errorf("Profiles = %v, want [alice bob]", names)
+	}
+
+	if err := RenameProfile("bob", "alice"); err == nil {
+		t.Error("RenameProfile over an existing profile succeeded")
+	}
+	if err := RenameProfile("bob", "carol"); err != nil {
+		t.Fatalf("RenameProfile: %v", err)
+	}
+	carol, err := ProfilePath("carol")
+	if err != nil {
+		t.Fatalf("ProfilePath: %v", err)
+	}
+	snapshot, err := Load(carol)
+	if err != nil || snapshot == nil {
+		t.Fatalf("Load renamed profile: %v", err)
+	}
+	if got := snapshot.Config.TrainingMaxes[config.Squat]; got != 325 {
+		t.Errorf("renamed profile Squat TM = %g, want 325", got)
+	}
+
+	if err := DeleteProfile("alice"); err != nil {
+		t.Fatalf("DeleteProfile: %v", err)
+	}
+	if err := DeleteProfile("alice"); err == nil {
+		t.Error("DeleteProfile of a missing profile succeeded")
+	}
+	if names, _ = Profiles(); !slices.Equal(names, []string{"carol"}) {
+		t.Errorf("Profiles after delete = %v, want [carol]", names)
+	}
+}
+
+// TestValidateProfileName checks names that could escape the profiles
+// directory are refused
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"alice", "Team-A_2", "j.doe"} {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("ValidateProfileName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", ".hidden", "..", "../alice", "a/b", "two words"} {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("ValidateProfileName(%q) succeeded, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"fmt"
+	"slices"
+
+	"lifting/config"
+	"lifting/e1rm"
+)
+
+// ProgressionResetGap is how far 90% of a lift's e1RM must fall below its
+// training max before ProgressionRules.Reset resets the training max
+const ProgressionResetGap = 0.1
+
+// AMRAPLog is the reps made on one AMRAP set of a program, imported from
+// Hevy or entered by hand
+type AMRAPLog struct {
+	Lift       config.Lift
+	Cycle      int
+	Percentage float64 // percentage of the training max
+	Weight     float64 // weight lifted
+	MinReps    int     // prescribed minimum, e.g. 1 for "1+"
+	Reps       int     // reps made
+}
+
+// ProgressionRules controls performance-based training max progression
+type ProgressionRules struct {
+	// Reset drops a training max to 90% of the e1RM when that is more than
+	// ProgressionResetGap below it
+	Reset   bool
+	Formula e1rm.Formula // formula for Reset, one of e1rm.Formulas
+}
+
+// NewProgressionRules returns the default rules: no resets, and
+// e1rm.DefaultFormula should Reset be turned on
+func NewProgressionRules() ProgressionRules {
+	return ProgressionRules{Formula: e1rm.DefaultFormula}
+}
+
+// ProgressionDecision is what happens to a lift's training max
+type ProgressionDecision string
+
+const (
+	ProgressionIncrease ProgressionDecision = "increase" // standard increment
+	ProgressionHold     ProgressionDecision = "hold"     // keep the training max
+	ProgressionReset    ProgressionDecision = "reset"    // 90% of the e1RM
+)
+
+// ProgressionResult is the training max decision for one lift and why
+type ProgressionResult struct {
+	Lift        config.Lift
+	Decision    ProgressionDecision
+	Reason      string
+	Previous    float64 // training max of the last cycle run
+	TrainingMax float64 // training max for the next cycle
+}
+
+// NextCycleConfigFromAMRAP is NextCycleConfig with performance-based
+// progression. Each lift is judged by its heaviest logged AMRAP set (the
+// week-3 1+ set), the latest cycle's if several were logged: missing the
+// minimum reps holds the training max, and making them keeps the standard
+// increase. With rules.Reset, a lift whose e1RM fell far behind is reset to
+// 90% of it instead. Lifts with no logged AMRAP get the standard increase.
+// It fails if rules.Formula is not a supported formula.
+func NextCycleConfigFromAMRAP(cfg *config.Config, logs []AMRAPLog, rules ProgressionRules) (*config.Config, []ProgressionResult, error) {
+	if !slices.Contains(e1rm.Formulas, rules.Formula) {
+		return nil, nil, fmt.Errorf("unknown e1RM formula %q", rules.Formula)
+	}
+	next := NextCycleConfig(cfg)
+	if next == nil {
+		return nil, nil, nil
+	}
+	last := lastCycleConfig(cfg)
+
+	judged := make(map[config.Lift]AMRAPLog)
+	for _, log := range logs {
+		current, ok := judged[log.Lift]
+		if !ok || log.Percentage > current.Percentage || (log.Percentage == current.Percentage && log.Cycle >= current.Cycle) {
+			judged[log.Lift] = log
+		}
+	}
+
+	unit := cfg.Units
+	var results []ProgressionResult
+	for _, lift := range cfg.Lifts() {
+		previous := last.TrainingMaxes[lift]
+		if previous <= 0 {
+			continue
+		}
+		result := ProgressionResult{
+			Lift:     lift,
+			Decision: ProgressionIncrease,
+			Previous: previous,
+		}
+
+		log, ok := judged[lift]
+		estimate, err := rules.Formula.Estimate(e1rm.Result{Weight: log.Weight, Reps: log.Reps})
+		switch {
+		case !ok:
+			result.Reason = "no AMRAP result logged, standard increase"
+		case rules.Reset && err == nil && config.CalculateTrainingMax(estimate) < previous*(1-ProgressionResetGap):
+			result.Decision = ProgressionReset
+			result.Reason = fmt.Sprintf("%s x%d is an e1RM of %s, far behind the training max; reset to 90%% of it", unit.Format(log.Weight), log.Reps, unit.Format(estimate))
+			next.TrainingMaxes[lift] = config.CalculateTrainingMax(estimate)
+		case log.Reps < log.MinReps:
+			result.Decision = ProgressionHold
+			result.Reason = fmt.Sprintf("%s x%d missed the %d+ minimum; holding the training max", unit.Format(log.Weight), log.Reps, log.MinReps)
+			next.TrainingMaxes[lift] = previous
+		default:
+			result.Reason = fmt.Sprintf("%s x%d made the %d+ minimum, standard increase", unit.Format(log.Weight), log.Reps, log.MinReps)
+		}
+
+		result.TrainingMax = next.TrainingMaxes[lift]
+		results = append(results, result)
+	}
+
+	return next, results, nil
+}
+
+// lastCycleConfig returns the config as it was for the last cycle of its
+// program: the config itself, or the last leader/anchor cycle of a block
+func lastCycleConfig(cfg *config.Config) *config.Config {
+	last := cfg.Clone()
+	if last.Block != nil {
+		for i := 1; i < last.Block.LeaderCycles+last.Block.AnchorCycles; i++ {
+			last.AdvanceCycle()
+		}
+	}
+	return last
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"testing"
+
+	"lifting/config"
+	"lifting/e1rm"
+)
+
+// TestNextCycleConfigFromAMRAP checks each lift's hold, increase or reset
+// decision from its logged AMRAP set
+func TestNextCycleConfigFromAMRAP(t *testing.T) {
+	logs := []AMRAPLog{
+		{Lift: config.Squat, Cycle: 1, Percentage: 95, Weight: 285, MinReps: 1, Reps: 5},
+		{Lift: config.Squat, Cycle: 1, Percentage: 85, Weight: 255, MinReps: 5, Reps: 2}, // not the heaviest set
+		{Lift: config.Bench, Cycle: 1, Percentage: 95, Weight: 190, MinReps: 1, Reps: 0},
+		{Lift: config.Deadlift, Cycle: 1, Percentage: 95, Weight: 300, MinReps: 1, Reps: 1},
+	}
+
+	type decision struct {
+		decision    ProgressionDecision
+		trainingMax float64
+	}
+	tests := []struct {
+		name  string
+		reset bool
+		want  map[config.Lift]decision
+	}{
+		{
+			name: "standard",
+			want: map[config.Lift]decision{
+				config.Squat:    {ProgressionIncrease, 310},
+				config.Bench:    {ProgressionHold, 200},
+				config.Deadlift: {ProgressionIncrease, 410},
+				config.OHP:      {ProgressionIncrease, 135},
+			},
+		},
+		{
+			// 90% of a 300 e1RM is far behind the 400 deadlift TM
+			name:  "reset",
+			reset: true,
+			want: map[config.Lift]decision{
+				config.Squat:    {ProgressionIncrease, 310},
+				config.Bench:    {ProgressionHold, 200},
+				config.Deadlift: {ProgressionReset, 270},
+				config.OHP:      {ProgressionIncrease, 135},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cfg.TrainingMaxes = config.LiftMaxes{config.Squat: 300, config.Bench: 200, config.Deadlift: 400, config.OHP: 130}
+			rules := NewProgressionRules()
+			rules.Reset = tt.reset
+
+			next, results, err := NextCycleConfigFromAMRAP(cfg, logs, rules)
+			if err != nil {
+				t.Fatalf("NextCycleConfigFromAMRAP: %v", err)
+			}
+			if len(results) != len(tt.want) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.want))
+			}
+			for _, result := range results {
+				want := tt.want[result.Lift]
+				if result.Decision != want.decision || result.TrainingMax != want.trainingMax {
+					t.Errorf("%s: %s to %g, want %s to %g", result.Lift, result.Decision, result.TrainingMax, want.decision, want.trainingMax)
+				}
+				if got := next.TrainingMaxes[result.Lift]; got != want.trainingMax {
+					t.Errorf("%s next TM = %g, want %g", result.Lift, got, want.trainingMax)
+				}
+			}
+		})
+	}
+}
+
+// TestNextCycleConfigFromAMRAPRejectsFormula checks rules without a
+// supported formula are refused rather than silently never resetting
+func TestNextCycleConfigFromAMRAPRejectsFormula(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.TrainingMaxes = config.LiftMaxes{config.Squat: 300, config.Bench: 200, config.Deadlift: 400, config.OHP: 130}
+
+	for _, formula := range []e1rm.Formula{"", "lombardi"} {
+		rules := ProgressionRules{Reset: true, Formula: formula}
+		if _, _, err := NextCycleConfigFromAMRAP(cfg, nil, rules); err == nil {
+			t.Errorf("formula %q accepted, want an error", formula)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package plates
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// scale converts plate weights to integer units so sums stay exact
+// (1/1000 of a unit covers 1.25 kg and 0.25 lb change plates)
+const scale = 1000
+
+// Plate is one plate size and how many pairs of it are available
+type Plate struct {
+	Weight float64
+	Pairs  int // 0 means unlimited
+}
+
+// Inventory describes the bar and plates available in a gym
+type Inventory struct {
+	BarWeight float64
+	Plates    []Plate
+}
+
+// Standard returns a typical commercial gym setup for the given unit
+// ("lbs" or "kg") with unlimited pairs of each plate
+func Standard(unit string) Inventory {
+	if unit == "kg" {
+		return Inventory{
+			BarWeight: 20,
+			Plates:    unlimited(25, 20, 15, 10, 5, 2.5, 1.25),
+		}
+	}
+	return Inventory{
+		BarWeight: 45,
+		Plates:    unlimited(45, 35, 25, 10, 5, 2.5),
+	}
+}
+
+// unlimited builds a plate list with no pair limits
+func unlimited(weights ...float64) []Plate {
+	plates := make([]Plate, len(weights))
+	for i, w := range weights {
+		plates[i] = Plate{Weight: w}
+	}
+	return plates
+}
+
+// ParseList parses a comma-separated plate list such as "45x4, 25x2, 10, 5".
+// The number after "x" is how many pairs are available; omit it for unlimited.
+func ParseList(value string) ([]Plate, error) {
+	var plates []Plate
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		weightStr, pairsStr, hasPairs := strings.Cut(strings.ToLower(item), "x")
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("invalid plate weight %q", item)
+		}
+
+		plate := Plate{Weight: weight}
+		if hasPairs {
+			pairs, err := strconv.Atoi(strings.TrimSpace(pairsStr))
+			if err != nil || pairs <= 0 {
+				return nil, fmt.Errorf("invalid pair count %q", item)
+			}
+			plate.Pairs = pairs
+		}
+		plates = append(plates, plate)
+	}
+
+	if len(plates) == 0 {
+		return nil, fmt.Errorf("no plates listed")
+	}
+	return plates, nil
+}
+
+// Floor returns the heaviest loadable weight at or below weight
+// (never less than the empty bar)
+func (inv Inventory) Floor(weight float64) float64 {
+	totals := inv.loadable(weight)
+	i := sort.SearchFloat64s(totals, weight+1e-9)
+	if i == 0 {
+		return totals[0]
+	}
+	return totals[i-1]
+}
+
+// Ceil returns the lightest loadable weight at or above weight
+// (or the heaviest loadable weight if weight is out of reach)
+func (inv Inventory) Ceil(weight float64) float64 {
+	totals := inv.loadable(weight)
+	i := sort.SearchFloat64s(totals, weight-1e-9)
+	if i == len(totals) {
+		return totals[len(totals)-1]
+	}
+	return totals[i]
+}
+
+// Nearest returns the loadable weight closest to weight, rounding ties up
+func (inv Inventory) Nearest(weight float64) float64 {
+	down, up := inv.Floor(weight), inv.Ceil(weight)
+	if weight-down < up-weight {
+		return down
+	}
+	return up
+}
+
+// loadable returns every loadable bar weight (sorted) up to one step past
+// weight, so callers can search on either side of it
+func (inv Inventory) loadable(weight float64) []float64 {
+	unit := inv.unit()
+	largest := 0
+	for _, p := range inv.Plates {
+		largest = max(largest, toUnits(p.Weight))
+	}
+	limit := max(0, toUnits((weight-inv.BarWeight)/2)) + largest
+	steps := limit / unit
+
+	// Bounded knapsack over per-side totals, in multiples of unit
+	reachable := make([]bool, steps+1)
+	reachable[0] = true
+	for _, p := range inv.Plates {
+		size := toUnits(p.Weight) / unit
+		if size <= 0 {
+			continue
+		}
+		count := p.Pairs
+		if count == 0 {
+			count = steps / size
+		}
+		for c := 0; c < count; c++ {
+			changed := false
+			for s := steps; s >= size; s-- {
+				if reachable[s-size] && !reachable[s] {
+					reachable[s] = true
+					changed = true
+				}
+			}
+			if !changed {
+				break
+			}
+		}
+	}
+
+	var totals []float64
+	for s, ok := range reachable {
+		if ok {
+			totals = append(totals, inv.BarWeight+2*float64(s*unit)/scale)
+		}
+	}
+	return totals
+}
+
+// unit returns the greatest common divisor of all plate sizes in integer units
+func (inv Inventory) unit() int {
+	g := 0
+	for _, p := range inv.Plates {
+		g = gcd(g, toUnits(p.Weight))
+	}
+	if g == 0 {
+		return scale
+	}
+	return g
+}
+
+func toUnits(weight float64) int {
+	return int(math.Round(weight * scale))
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// PerSide returns the plates to load on each side of the bar for weight,
+// heaviest first, using as few plates as possible within the inventory
+func (inv Inventory) PerSide(weight float64) ([]float64, error) {
+	if weight < inv.BarWeight-1e-9 {
+		return nil, fmt.Errorf("%g is lighter than the %g bar", weight, inv.BarWeight)
+	}
+
+	unit := inv.unit()
+	target := toUnits((weight - inv.BarWeight) / 2)
+	if target%unit != 0 {
+		return nil, fmt.Errorf("%g cannot be loaded with the available plates", weight)
+	}
+	steps := target / unit
+
+	// Lightest first, so heavier plates win ties (45+25+5 over 25+25+25)
+	sorted := append([]Plate{}, inv.Plates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Weight < sorted[j].Weight })
+
+	// fewest[s] is the fewest plates reaching per-side total s; taken[t][s]
+	// is how many of plate t that solution uses
+	const unreachable = math.MaxInt
+	fewest := make([]int, steps+1)
+	for s := 1; s <= steps; s++ {
+		fewest[s] = unreachable
+	}
+	taken := make([][]int, len(sorted))
+	for t, p := range sorted {
+		size := toUnits(p.Weight) / unit
+		taken[t] = make([]int, steps+1)
+		if size <= 0 {
+			continue
+		}
+		limit := p.Pairs
+		if limit == 0 {
+			limit = steps / size
+		}
+
+		next := make([]int, steps+1)
+		for s := 0; s <= steps; s++ {
+			next[s] = fewest[s]
+			for c := 1; c <= limit && c*size <= s; c++ {
+				if prev := fewest[s-c*size]; prev != unreachable && prev+c <= next[s] {
+					next[s] = prev + c
+					taken[t][s] = c
+				}
+			}
+		}
+		fewest = next
+	}
+	if fewest[steps] == unreachable {
+		return nil, fmt.Errorf("%g cannot be loaded with the available plates", weight)
+	}
+
+	// Walk back from the last plate type to recover the counts
+	counts := make([]int, len(sorted))
+	for t, s := len(sorted)-1, steps; t >= 0; t-- {
+		counts[t] = taken[t][s]
+		s -= counts[t] * (toUnits(sorted[t].Weight) / unit)
+	}
+
+	var perSide []float64
+	for t := len(sorted) - 1; t >= 0; t-- {
+		for c := 0; c < counts[t]; c++ {
+			perSide = append(perSide, sorted[t].Weight)
+		}
+	}
+	return perSide, nil
+}
+
+// Format renders a per-side plate list, e.g. "45+25+2.5" or "empty bar"
+func Format(perSide []float64) string {
+	if len(perSide) == 0 {
+		return "empty bar"
+	}
+	parts := make([]string, len(perSide))
+	for i, w := range perSide {
+		parts[i] = strconv.FormatFloat(w, 'f', -1, 64)
+	}
+	return strings.Join(parts, "+")
+}
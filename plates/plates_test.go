@@ -0,0 +1,112 @@
+package plates
+
+import (
+	"testing"
+)
+
+// TestRounding checks Floor, Ceil and Nearest only return weights the
+// inventory can load
+func TestRounding(t *testing.T) {
+	limited := Inventory{BarWeight: 45, Plates: []Plate{{Weight: 45, Pairs: 1}}}
+
+	tests := []struct {
+		name                 string
+		inv                  Inventory
+		weight               float64
+		floor, ceil, nearest float64
+	}{
+		{name: "lbs loadable", inv: Standard("lbs"), weight: 225, floor: 225, ceil: 225, nearest: 225},
+		{name: "lbs nearer down", inv: Standard("lbs"), weight: 232, floor: 230, ceil: 235, nearest: 230},
+		{name: "lbs nearer up", inv: Standard("lbs"), weight: 233, floor: 230, ceil: 235, nearest: 235},
+		{name: "lbs tie rounds up", inv: Standard("lbs"), weight: 232.5, floor: 230, ceil: 235, nearest: 235},
+		{name: "kg change plates", inv: Standard("kg"), weight: 101, floor: 100, ceil: 102.5, nearest: 100},
+		{name: "kg nearer up", inv: Standard("kg"), weight: 102, floor: 100, ceil: 102.5, nearest: 102.5},
+		{name: "below the bar", inv: Standard("lbs"), weight: 30, floor: 45, ceil: 45, nearest: 45},
+		{name: "limited pairs", inv: limited, weight: 120, floor: 45, ceil: 135, nearest: 135},
+		{name: "out of reach", inv: limited, weight: 300, floor: 135, ceil: 135, nearest: 135},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.inv.Floor(tt.weight); got != tt.floor {
+				t.Errorf("Floor(%g) = %g, want %g", tt.weight, got, tt.floor)
+			}
+			if got := tt.inv.Ceil(tt.weight); got != tt.ceil {
+				t.Errorf("Ceil(%g) = %g, want %g", tt.weight, got, tt.ceil)
+			}
+			if got := tt.inv.Nearest(tt.weight); got != tt.nearest {
+				t.Errorf("Nearest(%g) = %g, want %g", tt.weight, got, tt.nearest)
+			}
+		})
+	}
+}
+
+// TestParseList checks plate lists with and without pair counts
+func TestParseList(t *testing.T) {
+	got, err := ParseList("45x4, 25x2, 10, 2.5")
+	if err != nil {
+		t.Fatalf("ParseList: %v", err)
+	}
+	want := []Plate{{Weight: 45, Pairs: 4}, {Weight: 25, Pairs: 2}, {Weight: 10}, {Weight: 2.5}}
+	if len(got) != len(want) {
+		t.Fatalf("ParseList = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("plate %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	for _, value := range []string{"", " , ", "heavy", "-5", "45x0", "45xtwo"} {
+		if _, err := ParseList(value); err == nil {
+			t.Errorf("ParseList(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+// TestPerSide checks the per-side breakdown uses the fewest plates the
+// inventory allows, and refuses weights it can't load
+func TestPerSide(t *testing.T) {
+	limited := Inventory{
+		BarWeight: 45,
+		Plates:    []Plate{{Weight: 45, Pairs: 1}, {Weight: 25, Pairs: 2}, {Weight: 10}, {Weight: 5}},
+	}
+
+	tests := []struct {
+		name    string
+		inv     Inventory
+		weight  float64
+		want    string
+		wantErr bool
+	}{
+		{name: "lbs bar only", inv: Standard("lbs"), weight: 45, want: "empty bar"},
+		{name: "lbs", inv: Standard("lbs"), weight: 315, want: "45+45+45"},
+		{name: "lbs heavier plates win ties", inv: Standard("lbs"), weight: 195, want: "45+25+5"},
+		{name: "lbs change plate", inv: Standard("lbs"), weight: 230, want: "45+45+2.5"},
+		{name: "kg bar only", inv: Standard("kg"), weight: 20, want: "empty bar"},
+		{name: "kg", inv: Standard("kg"), weight: 100, want: "25+15"},
+		{name: "kg change plate", inv: Standard("kg"), weight: 102.5, want: "25+15+1.25"},
+		{name: "limited pairs", inv: limited, weight: 275, want: "45+25+25+10+10"},
+		{name: "limited pairs exhausted", inv: Inventory{BarWeight: 45, Plates: []Plate{{Weight: 45, Pairs: 1}}}, weight: 225, wantErr: true},
+		{name: "no plate small enough", inv: Standard("lbs"), weight: 187.5, wantErr: true},
+		{name: "lighter than the bar", inv: Standard("kg"), weight: 15, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perSide, err := tt.inv.PerSide(tt.weight)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("PerSide(%g) = %v, want an error", tt.weight, perSide)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PerSide(%g): %v", tt.weight, err)
+			}
+			if got := Format(perSide); got != tt.want {
+				t.Errorf("PerSide(%g) = %s, want %s", tt.weight, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package program
+
+import (
+	"lifting/config"
+)
+
+// generateBlock creates every cycle of cfg.Block: leader cycles, the
+// optional 7th week, then anchor cycles. Standard TM increases are applied
+// after each leader and anchor cycle, so the 7th week and anchor run on the
+// increased maxes. Block cycles only deload when the deload policy asks for it.
+func generateBlock(cfg *config.Config) (*Program, error) {
+	block := cfg.Block
+
+	leaderName := block.LeaderTemplate
+	if leaderName == "" {
+		leaderName = cfg.Template
+	}
+	leader, err := LookupTemplate(leaderName)
+	if err != nil {
+		return nil, err
+	}
+
+	var anchor Template
+	if block.AnchorCycles > 0 {
+		if anchor, err = LookupTemplate(block.AnchorTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	program := &Program{
+		Template: leader.Name(),
+		Units:    cfg.Units,
+	}
+
+	current := cfg.Clone()
+	cycle := 1
+	for i := 0; i < block.LeaderCycles; i++ {
+		program.Days = append(program.Days, generateCycle(current, leader, cycle, PhaseLeader, current.HasDeload(true))...)
+		current.AdvanceCycle()
+		cycle++
+	}
+
+	if block.SeventhWeek != config.SeventhWeekNone {
+		program.AppendSeventhWeek(current, block.SeventhWeek)
+		cycle++
+	}
+
+	for i := 0; i < block.AnchorCycles; i++ {
+		program.Days = append(program.Days, generateCycle(current, anchor, cycle, PhaseAnchor, current.HasDeload(true))...)
+		current.AdvanceCycle()
+		cycle++
+	}
+
+	return program, nil
+}
@@ -1,29 +1,119 @@
 package program
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"lifting/config"
+	"lifting/plates"
+)
+
+// SetKind identifies the role of a set so exporters can label it
+type SetKind string
+
+const (
+	SetWarmup       SetKind = "warmup"
+	SetWork         SetKind = "work"
+	SetAMRAP        SetKind = "amrap" // "+" top set: as many reps as possible
+	SetJoker        SetKind = "joker" // optional heavier set after the top set
+	SetDeload       SetKind = "deload"
+	SetSupplemental SetKind = "supplemental"
+	SetAccessory    SetKind = "accessory"
+	SetJump         SetKind = "jump"         // jumps before the main lift
+	SetThrow        SetKind = "throw"        // throws before the main lift
+	SetConditioning SetKind = "conditioning" // conditioning at the end of the day
 )
 
 // Set represents a single set in the program
 type Set struct {
+	Kind       SetKind
 	Exercise   string
 	Sets       int
 	Reps       string // string to support "5+" notation
 	Weight     float64
 	Percentage float64
+	Plates     []float64 // per-side plate breakdown, set when the config has a plate inventory
+
+	// Assistance details (accessory sets only)
+	Category  config.AssistanceCategory
+	TotalReps string // total-rep target across all sets, e.g. "50-100"
+	Rest      int    // rest between sets in seconds, 0 if unspecified
+
+	// Jumps, throws and conditioning are prescribed per set by duration
+	// and/or distance instead of weight
+	Duration int     // seconds per set, 0 if unspecified
+	Distance float64 // meters per set, 0 if unspecified
 }
 
+// Phase identifies which part of a leader/anchor block a day belongs to
+type Phase string
+
+const (
+	PhaseSingle      Phase = ""         // a standalone cycle, not part of a block
+	PhaseLeader      Phase = "leader"   // leader cycle of a block
+	PhaseSeventhWeek Phase = "7th week" // 7th-week deload or TM test
+	PhaseAnchor      Phase = "anchor"   // anchor cycle of a block
+)
+
 // Day represents a training day
 type Day struct {
-	Week     int
+	Cycle    int    // cycle number within the program, starting at 1
+	Phase    Phase  // block phase, PhaseSingle outside of blocks
+	Template string // template (or 7th-week protocol) that produced the day
+	Week     int    // calendar week of the cycle
 	DayNum   int
-	MainLift config.Lift
+	MainLift config.Lift   // first main lift of the day
+	Lifts    []config.Lift // every main lift trained on the day, in order
 	Sets     []Set
 }
 
-// Program represents the full 4-week program
+// LiftNames joins the day's main lifts for titles, e.g. "Squat + Bench Press"
+func (d Day) LiftNames() string {
+	if len(d.Lifts) == 0 {
+		return string(d.MainLift)
+	}
+	names := make([]string, len(d.Lifts))
+	for i, lift := range d.Lifts {
+		names[i] = string(lift)
+	}
+	return strings.Join(names, " + ")
+}
+
+// Program represents the generated cycle (or block of cycles)
 type Program struct {
-	Days []Day
+	Template string      // name of the template that generated the program
+	Units    config.Unit // unit all set weights are expressed in
+	Days     []Day
+}
+
+// Label returns the short program name used for routine and folder titles,
+// e.g. "531 BBB"
+func (p *Program) Label() string {
+	return "531 " + strings.ToUpper(p.Template)
+}
+
+// IsBlock reports whether the program spans a leader/anchor block
+func (p *Program) IsBlock() bool {
+	return len(p.Days) > 0 && p.Days[0].Phase != PhaseSingle
+}
+
+// RoutineTitle returns a unique title for a day, e.g. "531 BBB W1D1 - Squat"
+// or, within a block, "531 BBB C2W1D1 - Squat"
+func (p *Program) RoutineTitle(day Day) string {
+	if !p.IsBlock() {
+		return fmt.Sprintf("%s W%dD%d - %s", p.Label(), day.Week, day.DayNum, day.LiftNames())
+	}
+	return fmt.Sprintf("531 %s C%dW%dD%d - %s", strings.ToUpper(day.Template), day.Cycle, day.Week, day.DayNum, day.LiftNames())
+}
+
+// FolderTitle returns the routine folder for a day: one folder per week for
+// a single cycle, or one per cycle within a block
+func (p *Program) FolderTitle(day Day) string {
+	if !p.IsBlock() {
+		return fmt.Sprintf("%s Week %d", p.Label(), day.Week)
+	}
+	return fmt.Sprintf("531 Block C%d - %s %s", day.Cycle, strings.ToUpper(day.Template), day.Phase)
 }
 
 // WeekScheme defines the percentages and reps for a week's main lift work
@@ -32,12 +122,6 @@ type WeekScheme struct {
 	Reps        []string
 }
 
-// Warmup scheme: 40% x5, 50% x5, 60% x3
-var WarmupScheme = WeekScheme{
-	Percentages: []float64{40, 50, 60},
-	Reps:        []string{"5", "5", "3"},
-}
-
 // Working set schemes for weeks 1-3
 var WorkingSchemes = map[int]WeekScheme{
 	1: {
@@ -60,62 +144,220 @@ var DeloadScheme = WeekScheme{
 	Reps:        []string{"5", "5", "5"},
 }
 
-// Generate creates a full 4-week 5/3/1 BBB program from the given config
-func Generate(cfg *config.Config) *Program {
+// Generate creates one 5/3/1 cycle from the given config using the config's
+// template, or every cycle of the config's block when one is set. Cycle
+// length and deload weeks follow the config. It refuses configs that fail
+// validation rather than emitting empty sets.
+func Generate(cfg *config.Config) (*Program, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+	if cfg.Block != nil {
+		return generateBlock(cfg)
+	}
+
+	template, err := LookupTemplate(cfg.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	// A 7th week takes the place of the deload week
 	program := &Program{
-		Days: make([]Day, 0, 16), // 4 weeks x 4 days
+		Template: template.Name(),
+		Units:    cfg.Units,
+		Days:     generateCycle(cfg, template, 1, PhaseSingle, cfg.HasDeload(false) && cfg.SeventhWeek == config.SeventhWeekNone),
 	}
+	if cfg.SeventhWeek != config.SeventhWeekNone {
+		program.AppendSeventhWeek(cfg, cfg.SeventhWeek)
+	}
+	return program, nil
+}
 
-	for week := 1; week <= 4; week++ {
-		for dayIdx, mainLift := range cfg.LiftOrder {
-			day := Day{
-				Week:     week,
-				DayNum:   dayIdx + 1,
-				MainLift: mainLift,
-				Sets:     make([]Set, 0),
-			}
+// generateCycle creates the days of one cycle: cfg.CycleWeeks working weeks,
+// plus a deload week when deload is set
+func generateCycle(cfg *config.Config, template Template, cycle int, phase Phase, deload bool) []Day {
+	weeks := make([]int, 0, cfg.CycleWeeks+1)
+	for week := 1; week <= cfg.CycleWeeks; week++ {
+		weeks = append(weeks, week)
+	}
+	if deload {
+		weeks = append(weeks, 4)
+	}
 
-			trainingMax := cfg.TrainingMaxes[mainLift]
+	plans := planDays(cfg, weeks)
+	days := make([]Day, 0, len(plans))
+	for _, plan := range plans {
+		day := plan.day(cycle, phase, template.Name(), 0)
 
-			// Add sets based on whether it's a deload week
-			if week == 4 {
-				// Deload week - just the deload sets (no warmup, they're the same)
-				day.Sets = append(day.Sets, generateMainSets(mainLift, trainingMax, DeloadScheme)...)
-			} else {
-				// Regular week - warmup + working sets
-				day.Sets = append(day.Sets, generateMainSets(mainLift, trainingMax, WarmupScheme)...)
-				day.Sets = append(day.Sets, generateMainSets(mainLift, trainingMax, WorkingSchemes[week])...)
-			}
+		// Jumps and throws first, then main and supplemental work from the
+		// template for each lift (scheme week 4 is the deload), the
+		// accessories for each lift and finally conditioning
+		day.Sets = append(day.Sets, conditioningSets(cfg, plan.Slots, true)...)
+		for _, slot := range plan.Slots {
+			slot.Deload = slot.Week == 4
+			day.Sets = append(day.Sets, template.Sets(slot)...)
+		}
+		for _, slot := range plan.Slots {
+			day.Sets = append(day.Sets, accessorySets(cfg, slot.Lift)...)
+		}
+		day.Sets = append(day.Sets, conditioningSets(cfg, plan.Slots, false)...)
+
+		if cfg.Plates != nil {
+			annotatePlates(day.Sets, *cfg.Plates)
+		}
+
+		days = append(days, day)
+	}
 
-			// BBB sets (5x10 at configured percentage)
-			bbbLift := cfg.BBBPairing[mainLift]
-			bbbTrainingMax := cfg.TrainingMaxes[bbbLift]
-			day.Sets = append(day.Sets, generateBBBSets(bbbLift, bbbTrainingMax, cfg.BBBPercentage)...)
-
-			// Accessory (5x10, no weight)
-			if accessory, ok := cfg.Accessories[mainLift]; ok && accessory != "" {
-				day.Sets = append(day.Sets, Set{
-					Exercise:   accessory,
-					Sets:       5,
-					Reps:       "10",
-					Weight:     0,
-					Percentage: 0,
-				})
+	return days
+}
+
+// dayPlan is one training day of a cycle: its calendar week and day, and the
+// main lift slots trained on it
+type dayPlan struct {
+	Week   int
+	DayNum int
+	Slots  []Slot
+}
+
+// planDays lays out main lift work for the given 5/3/1 scheme weeks across
+// cfg.DaysPerWeek training days. Two days a week pairs consecutive lifts
+// (Squat+Bench and Deadlift+OHP with the default order); otherwise each day
+// trains one lift and, with fewer days than lifts, the lifts roll over into
+// the next calendar week.
+func planDays(cfg *config.Config, weeks []int) []dayPlan {
+	daysPerWeek := cfg.DaysPerWeek
+	liftsPerDay := 1
+	if daysPerWeek == 2 {
+		liftsPerDay = (len(cfg.LiftOrder) + 1) / 2
+	}
+
+	var plans []dayPlan
+	for _, week := range weeks {
+		for i := 0; i < len(cfg.LiftOrder); i += liftsPerDay {
+			n := len(plans)
+			plan := dayPlan{Week: n/daysPerWeek + 1, DayNum: n%daysPerWeek + 1}
+			for _, lift := range cfg.LiftOrder[i:min(i+liftsPerDay, len(cfg.LiftOrder))] {
+				plan.Slots = append(plan.Slots, Slot{Config: cfg, Week: week, Lift: lift})
 			}
+			plans = append(plans, plan)
+		}
+	}
+	return plans
+}
+
+// day returns an empty Day for the plan, with its week offset by weekOffset
+func (p dayPlan) day(cycle int, phase Phase, template string, weekOffset int) Day {
+	lifts := make([]config.Lift, len(p.Slots))
+	for i, slot := range p.Slots {
+		lifts[i] = slot.Lift
+	}
+	return Day{
+		Cycle:    cycle,
+		Phase:    phase,
+		Template: template,
+		Week:     p.Week + weekOffset,
+		DayNum:   p.DayNum,
+		MainLift: lifts[0],
+		Lifts:    lifts,
+		Sets:     make([]Set, 0),
+	}
+}
+
+// accessorySets returns the assistance work for a main lift day. Total-rep
+// targets are split evenly into a per-set rep range.
+func accessorySets(cfg *config.Config, mainLift config.Lift) []Set {
+	work := cfg.AssistanceFor(mainLift)
+	sets := make([]Set, 0, len(work))
+	for _, a := range work {
+		set := Set{
+			Kind:     SetAccessory,
+			Exercise: a.Exercise,
+			Sets:     a.Sets,
+			Reps:     strconv.Itoa(a.Reps),
+			Weight:   a.Weight,
+			Category: a.Category,
+			Rest:     a.Rest,
+		}
+		if set.Category == "" {
+			set.Category = config.CategoryOf(a.Exercise)
+		}
+		if target := a.TotalReps; target != nil {
+			set.TotalReps = fmt.Sprintf("%d-%d", target.Min, target.Max)
+			set.Reps = fmt.Sprintf("%d-%d", ceilDiv(target.Min, a.Sets), ceilDiv(target.Max, a.Sets))
+		}
+		sets = append(sets, set)
+	}
+	return sets
+}
+
+// conditioningKinds maps conditioning entry kinds to set kinds
+var conditioningKinds = map[config.ConditioningKind]SetKind{
+	config.ConditioningJumps:  SetJump,
+	config.ConditioningThrows: SetThrow,
+	config.ConditioningCardio: SetConditioning,
+}
 
-			program.Days = append(program.Days, day)
+// conditioningSets returns the jumps and throws (before is true) or the
+// conditioning (before is false) for the lifts trained on a day
+func conditioningSets(cfg *config.Config, slots []Slot, before bool) []Set {
+	var sets []Set
+	for _, slot := range slots {
+		for _, entry := range cfg.Conditioning[slot.Lift] {
+			if entry.BeforeMainWork() != before {
+				continue
+			}
+			set := Set{
+				Kind:     conditioningKinds[entry.Kind],
+				Exercise: entry.Exercise,
+				Sets:     entry.Sets,
+				Duration: entry.Duration,
+				Distance: entry.Distance,
+			}
+			if entry.Reps > 0 {
+				set.Reps = strconv.Itoa(entry.Reps)
+			}
+			sets = append(sets, set)
 		}
 	}
+	return sets
+}
 
-	return program
+// ceilDiv divides a by b, rounding up
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
 }
 
-// generateMainSets creates sets for main lift work (warmup or working sets)
-func generateMainSets(lift config.Lift, trainingMax float64, scheme WeekScheme) []Set {
+// warmupSets creates a lift's warm-up sets from the config. Weights are
+// never lighter than the bar, and empty-bar sets have no percentage.
+func warmupSets(cfg *config.Config, lift config.Lift, trainingMax float64) []Set {
+	warmup := cfg.WarmupFor(lift)
+	sets := make([]Set, len(warmup))
+	for i, w := range warmup {
+		sets[i] = Set{
+			Kind:       SetWarmup,
+			Exercise:   string(lift),
+			Sets:       1,
+			Reps:       strconv.Itoa(w.Reps),
+			Weight:     max(cfg.RoundWeight(trainingMax*w.Percentage/100), cfg.BarWeight()),
+			Percentage: w.Percentage,
+		}
+	}
+	return sets
+}
+
+// generateMainSets creates sets for main lift work (warmup or working sets).
+// Working sets with "+" reps are marked as AMRAP sets.
+func generateMainSets(cfg *config.Config, lift config.Lift, trainingMax float64, scheme WeekScheme, kind SetKind) []Set {
 	sets := make([]Set, len(scheme.Percentages))
 	for i, pct := range scheme.Percentages {
-		weight := config.RoundToNearest5(trainingMax * pct / 100)
+		weight := cfg.RoundWeight(trainingMax * pct / 100)
+		setKind := kind
+		if kind == SetWork && strings.HasSuffix(scheme.Reps[i], "+") {
+			setKind = SetAMRAP
+		}
 		sets[i] = Set{
+			Kind:       setKind,
 			Exercise:   string(lift),
 			Sets:       1,
 			Reps:       scheme.Reps[i],
@@ -126,16 +368,19 @@ func generateMainSets(lift config.Lift, trainingMax float64, scheme WeekScheme)
 	return sets
 }
 
-// generateBBBSets creates the 5x10 BBB sets
-func generateBBBSets(lift config.Lift, trainingMax float64, percentage float64) []Set {
-	weight := config.RoundToNearest5(trainingMax * percentage / 100)
-	return []Set{
-		{
-			Exercise:   string(lift),
-			Sets:       5,
-			Reps:       "10",
-			Weight:     weight,
-			Percentage: percentage,
-		},
+// IsConditioning reports whether the set is a jump, throw or conditioning set
+func (s Set) IsConditioning() bool {
+	return s.Kind == SetJump || s.Kind == SetThrow || s.Kind == SetConditioning
+}
+
+// annotatePlates fills in the per-side plates for every barbell set
+func annotatePlates(sets []Set, inventory plates.Inventory) {
+	for i := range sets {
+		if sets[i].Kind == SetAccessory || sets[i].IsConditioning() || sets[i].Weight < inventory.BarWeight {
+			continue
+		}
+		if perSide, err := inventory.PerSide(sets[i].Weight); err == nil {
+			sets[i].Plates = perSide
+		}
 	}
 }
@@ -0,0 +1,78 @@
+package program
+
+import (
+	"lifting/config"
+)
+
+// 7th-week deload (5/3/1 Forever): 70% x5, 80% x3-5, 90% x1, 100% x1
+var SeventhWeekDeloadScheme = WeekScheme{
+	Percentages: []float64{70, 80, 90, 100},
+	Reps:        []string{"5", "3-5", "1", "1"},
+}
+
+// 7th-week training max test: 70%, 80%, 90%, 100% x5
+var TMTestScheme = WeekScheme{
+	Percentages: []float64{70, 80, 90, 100},
+	Reps:        []string{"5", "5", "5", "5"},
+}
+
+// SeventhWeekScheme returns the main work scheme for a 7th-week protocol
+func SeventhWeekScheme(protocol config.SeventhWeek) WeekScheme {
+	if protocol == config.SeventhWeekTMTest {
+		return TMTestScheme
+	}
+	return SeventhWeekDeloadScheme
+}
+
+// AppendSeventhWeek adds a 7th week using cfg's training maxes after the
+// last cycle of the program. In a block it runs as a cycle of its own,
+// starting a new calendar week like every block cycle; otherwise it follows
+// the last week of the cycle, starting in any training days that week has
+// left when there are fewer days per week than lifts.
+func (p *Program) AppendSeventhWeek(cfg *config.Config, protocol config.SeventhWeek) {
+	cycle, week, startDay := 1, 1, 0
+	if len(p.Days) > 0 {
+		last := p.Days[len(p.Days)-1]
+		if p.IsBlock() {
+			cycle = last.Cycle + 1
+		} else {
+			cycle, week, startDay = last.Cycle, last.Week, last.DayNum
+		}
+	}
+	p.Days = append(p.Days, seventhWeek(cfg, protocol, cycle, week, startDay)...)
+}
+
+// seventhWeek creates a one-week 7th-week protocol starting after startDay
+// training days of the given calendar week: any jumps and throws, warm-ups
+// and the protocol's sets for each main lift, then accessories and
+// conditioning
+func seventhWeek(cfg *config.Config, protocol config.SeventhWeek, cycle, week, startDay int) []Day {
+	scheme := SeventhWeekScheme(protocol)
+
+	plans := planDays(cfg, []int{1})
+	days := make([]Day, 0, len(plans))
+	for i, plan := range plans {
+		n := startDay + i
+		plan.Week, plan.DayNum = n/cfg.DaysPerWeek+1, n%cfg.DaysPerWeek+1
+		day := plan.day(cycle, PhaseSeventhWeek, string(protocol), week-1)
+
+		day.Sets = append(day.Sets, conditioningSets(cfg, plan.Slots, true)...)
+		for _, slot := range plan.Slots {
+			trainingMax := slot.TrainingMax(slot.Lift)
+			day.Sets = append(day.Sets, warmupSets(cfg, slot.Lift, trainingMax)...)
+			day.Sets = append(day.Sets, generateMainSets(cfg, slot.Lift, trainingMax, scheme, SetWork)...)
+		}
+		for _, slot := range plan.Slots {
+			day.Sets = append(day.Sets, accessorySets(cfg, slot.Lift)...)
+		}
+		day.Sets = append(day.Sets, conditioningSets(cfg, plan.Slots, false)...)
+
+		if cfg.Plates != nil {
+			annotatePlates(day.Sets, *cfg.Plates)
+		}
+
+		days = append(days, day)
+	}
+
+	return days
+}
@@ -0,0 +1,65 @@
+package program
+
+import (
+	"testing"
+
+	"lifting/config"
+)
+
+// TestSeventhWeekFillsPartialWeek checks a single cycle's 7th week starts in
+// the training days left in the last calendar week, so the calendar has no
+// empty training days
+func TestSeventhWeekFillsPartialWeek(t *testing.T) {
+	tests := []struct {
+		name        string
+		daysPerWeek int
+		cycleWeeks  int
+		first       [2]int // week and day of the first 7th-week day
+	}{
+		{name: "3 days, partial week", daysPerWeek: 3, cycleWeeks: 2, first: [2]int{3, 3}},
+		{name: "3 days, full weeks", daysPerWeek: 3, cycleWeeks: 3, first: [2]int{5, 1}},
+		{name: "4 days", daysPerWeek: 4, cycleWeeks: 3, first: [2]int{4, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cfg.TrainingMaxes = config.LiftMaxes{config.Squat: 315, config.Bench: 225, config.Deadlift: 405, config.OHP: 135}
+			cfg.DaysPerWeek = tt.daysPerWeek
+			cfg.CycleWeeks = tt.cycleWeeks
+			cfg.SeventhWeek = config.SeventhWeekTMTest
+
+			prog, err := Generate(cfg)
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+
+			seventh := 0
+			for i, day := range prog.Days {
+				// Every day follows the one before it in the calendar
+				want := [2]int{1, 1}
+				if i > 0 {
+					prev := prog.Days[i-1]
+					want = [2]int{prev.Week, prev.DayNum + 1}
+					if prev.DayNum == tt.daysPerWeek {
+						want = [2]int{prev.Week + 1, 1}
+					}
+				}
+				if got := [2]int{day.Week, day.DayNum}; got != want {
+					t.Errorf("day %d is week %d day %d, want week %d day %d", i+1, got[0], got[1], want[0], want[1])
+				}
+
+				if day.Phase != PhaseSeventhWeek {
+					continue
+				}
+				if seventh == 0 && [2]int{day.Week, day.DayNum} != tt.first {
+					t.Errorf("7th week starts on week %d day %d, want week %d day %d", day.Week, day.DayNum, tt.first[0], tt.first[1])
+				}
+				seventh++
+			}
+			if seventh != len(cfg.LiftOrder) {
+				t.Errorf("7th week has %d days, want %d", seventh, len(cfg.LiftOrder))
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package program
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lifting/config"
+)
+
+// Slot is one main lift's work on a training day, passed to a Template
+type Slot struct {
+	Config *config.Config
+	Week   int // week of the cycle (1-3 working weeks, 4 deload)
+	Lift   config.Lift
+	Deload bool
+}
+
+// TrainingMax returns the config's training max for a lift
+func (s Slot) TrainingMax(lift config.Lift) float64 {
+	return s.Config.TrainingMaxes[lift]
+}
+
+// Template builds the main and supplemental work for each slot of a program.
+// Accessories are added by Generate after the template's sets.
+type Template interface {
+	// Name is the identifier stored in config.Config.Template (e.g. "bbb")
+	Name() string
+
+	// Title is the human-readable template name (e.g. "Boring But Big")
+	Title() string
+
+	// Sets returns the main and supplemental sets for a slot
+	Sets(slot Slot) []Set
+}
+
+// templates holds every registered template by name
+var templates = make(map[string]Template)
+
+// Register makes a template available to Generate under its name, and to
+// config.Validate as a valid template name.
+// It panics if a template with the same name is already registered.
+func Register(t Template) {
+	name := strings.ToLower(t.Name())
+	if _, exists := templates[name]; exists {
+		panic(fmt.Sprintf("program: template %q registered twice", name))
+	}
+	templates[name] = t
+	config.RegisterTemplate(name)
+}
+
+// LookupTemplate returns the registered template with the given name
+func LookupTemplate(name string) (Template, error) {
+	t, ok := templates[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown template %q", name)
+	}
+	return t, nil
+}
+
+// Templates returns all registered templates sorted by name
+func Templates() []Template {
+	list := make([]Template, 0, len(templates))
+	for _, t := range templates {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// WeekScheme returns the main work scheme for the slot's week: the working
+// scheme for weeks 1-3 (all x5 under 5s PRO) or the deload scheme on a
+// deload week
+func (s Slot) WeekScheme() WeekScheme {
+	if s.Deload {
+		return DeloadScheme
+	}
+	scheme := WorkingSchemes[s.Week]
+	if s.Config.MainWork == config.MainWork5sPro {
+		scheme = fivesPro(scheme)
+	}
+	return scheme
+}
+
+// fivesPro returns a copy of a scheme with every set prescribed as 5 reps
+func fivesPro(scheme WeekScheme) WeekScheme {
+	reps := make([]string, len(scheme.Reps))
+	for i := range reps {
+		reps[i] = "5"
+	}
+	return WeekScheme{Percentages: scheme.Percentages, Reps: reps}
+}
+
+// MainWork returns the standard 5/3/1 main lift sets for a slot: warm-ups
+// plus the week's working sets and any Joker sets, or the deload sets on a
+// deload week (warmed up only when the config asks for it)
+func MainWork(slot Slot) []Set {
+	trainingMax := slot.TrainingMax(slot.Lift)
+	var sets []Set
+	if !slot.Deload || slot.Config.WarmupOnDeload {
+		sets = warmupSets(slot.Config, slot.Lift, trainingMax)
+	}
+	if slot.Deload {
+		return append(sets, generateMainSets(slot.Config, slot.Lift, trainingMax, DeloadScheme, SetDeload)...)
+	}
+
+	sets = append(sets, generateMainSets(slot.Config, slot.Lift, trainingMax, slot.WeekScheme(), SetWork)...)
+	return append(sets, jokerSets(slot)...)
+}
+
+// jokerSets returns the configured Joker sets for a slot. Jokers follow the
+// top set on triples and singles weeks, each one Step percent heavier.
+func jokerSets(slot Slot) []Set {
+	jokers := slot.Config.Jokers
+	scheme := slot.WeekScheme()
+	last := len(scheme.Percentages) - 1
+	if jokers.Sets <= 0 || slot.Deload || last < 0 {
+		return nil
+	}
+
+	reps := strings.TrimSuffix(scheme.Reps[last], "+")
+	if n, err := strconv.Atoi(reps); err != nil || n > 3 {
+		return nil
+	}
+
+	sets := make([]Set, jokers.Sets)
+	for i := range sets {
+		percentage := scheme.Percentages[last] + jokers.Step*float64(i+1)
+		sets[i] = PercentSet(slot, slot.Lift, 1, reps, percentage)
+		sets[i].Kind = SetJoker
+	}
+	return sets
+}
+
+// PercentSet returns sets x reps of a lift at a percentage of its training max,
+// marked as supplemental work
+func PercentSet(slot Slot, lift config.Lift, sets int, reps string, percentage float64) Set {
+	return Set{
+		Kind:       SetSupplemental,
+		Exercise:   string(lift),
+		Sets:       sets,
+		Reps:       reps,
+		Weight:     slot.Config.RoundWeight(slot.TrainingMax(lift) * percentage / 100),
+		Percentage: percentage,
+	}
+}
@@ -0,0 +1,48 @@
+package program
+
+import "strconv"
+
+func init() {
+	Register(boringButBig{})
+	Register(setLast{name: "fsl", title: "First Set Last", setIndex: 0})
+	Register(setLast{name: "ssl", title: "Second Set Last", setIndex: 1})
+}
+
+// boringButBig is 5/3/1 main work followed by 5x10 of the paired BBB lift
+// at the configured BBB percentage for the week
+type boringButBig struct{}
+
+func (boringButBig) Name() string  { return "bbb" }
+func (boringButBig) Title() string { return "Boring But Big" }
+
+func (boringButBig) Sets(slot Slot) []Set {
+	sets := MainWork(slot)
+
+	// BBB sets (5x10 at configured percentage)
+	bbbLift := slot.Config.BBBPairing[slot.Lift]
+	return append(sets, PercentSet(slot, bbbLift, 5, "10", slot.Config.BBBPercentageForWeek(slot.Week)))
+}
+
+// setLast is First Set Last / Second Set Last: 5/3/1 main work followed by
+// supplemental sets of the same lift at the week's first or second working
+// set percentage, using the config's Supplemental sets x reps. Deload weeks
+// skip the supplemental sets.
+type setLast struct {
+	name     string
+	title    string
+	setIndex int // index into the week's scheme percentages
+}
+
+func (t setLast) Name() string  { return t.name }
+func (t setLast) Title() string { return t.title }
+
+func (t setLast) Sets(slot Slot) []Set {
+	sets := MainWork(slot)
+	if slot.Deload {
+		return sets
+	}
+
+	percentage := slot.WeekScheme().Percentages[t.setIndex]
+	volume := slot.Config.Supplemental
+	return append(sets, PercentSet(slot, slot.Lift, volume.Sets, strconv.Itoa(volume.Reps), percentage))
+}
@@ -0,0 +1,42 @@
+package program
+
+import (
+	"testing"
+
+	"lifting/config"
+)
+
+// TestSetLastSkipsDeloadSupplemental checks FSL and SSL prescribe their
+// supplemental sets on working weeks only, not in the deload week
+func TestSetLastSkipsDeloadSupplemental(t *testing.T) {
+	for _, name := range []string{"fsl", "ssl"} {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cfg.TrainingMaxes = config.LiftMaxes{config.Squat: 315, config.Bench: 225, config.Deadlift: 405, config.OHP: 135}
+			cfg.Template = name
+
+			prog, err := Generate(cfg)
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+
+			deloadDays := 0
+			for _, day := range prog.Days {
+				deload, supplemental := false, false
+				for _, set := range day.Sets {
+					deload = deload || set.Kind == SetDeload
+					supplemental = supplemental || set.Kind == SetSupplemental
+				}
+				if deload {
+					deloadDays++
+				}
+				if supplemental == deload {
+					t.Errorf("week %d day %d: deload %v, supplemental sets %v", day.Week, day.DayNum, deload, supplemental)
+				}
+			}
+			if deloadDays == 0 {
+				t.Fatal("program has no deload days")
+			}
+		})
+	}
+}
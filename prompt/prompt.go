@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"lifting/config"
+	"lifting/e1rm"
+	"lifting/plates"
+	"lifting/program"
 )
 
 // Reader handles interactive prompts
@@ -51,6 +55,70 @@ func (r *Reader) readFloat(prompt string) float64 {
 	}
 }
 
+// readInt reads a positive integer from the user with validation
+func (r *Reader) readInt(prompt string) int {
+	for {
+		fmt.Print(prompt)
+		input := r.readLine()
+		val, err := strconv.Atoi(input)
+		if err != nil || val <= 0 {
+			fmt.Println("Please enter a valid positive whole number.")
+			continue
+		}
+		return val
+	}
+}
+
+// readCount reads a whole number that may be zero
+func (r *Reader) readCount(prompt string) int {
+	for {
+		fmt.Print(prompt)
+		input := r.readLine()
+		val, err := strconv.Atoi(input)
+		if err != nil || val < 0 {
+			fmt.Println("Please enter a valid whole number (0 or more).")
+			continue
+		}
+		return val
+	}
+}
+
+// readWeight reads a weight that may be zero for none
+func (r *Reader) readWeight(prompt string) float64 {
+	for {
+		fmt.Print(prompt)
+		input := r.readLine()
+		val, err := strconv.ParseFloat(input, 64)
+		if err != nil || val < 0 {
+			fmt.Println("Please enter a valid weight (0 or more).")
+			continue
+		}
+		return val
+	}
+}
+
+// readWeeks reads a number of working weeks between 1 and 3
+func (r *Reader) readWeeks(prompt string) int {
+	for {
+		weeks := r.readInt(prompt)
+		if weeks <= 3 {
+			return weeks
+		}
+		fmt.Println("A cycle has at most 3 working weeks.")
+	}
+}
+
+// readPercentStep reads a Joker step between 5 and 10 percent
+func (r *Reader) readPercentStep(prompt string) float64 {
+	for {
+		step := r.readFloat(prompt)
+		if step >= 5 && step <= 10 {
+			return step
+		}
+		fmt.Println("Please enter a value between 5 and 10.")
+	}
+}
+
 // readYesNo reads a yes/no response from the user
 func (r *Reader) readYesNo(prompt string) bool {
 	for {
@@ -84,12 +152,42 @@ func (r *Reader) readChoice(prompt string, options []string) int {
 	}
 }
 
+// ChooseProfile asks which athlete profile to use. It returns the name of an
+// existing or new profile, or "" to use the shared memory file.
+func (r *Reader) ChooseProfile(profiles []string, validate func(string) error) string {
+	fmt.Println("\n--- Athlete Profile ---")
+	options := append(append([]string{}, profiles...), "New profile", "No profile (shared memory file)")
+	choice := r.readChoice("Which athlete is this program for?", options)
+	switch {
+	case choice < len(profiles):
+		return profiles[choice]
+	case choice > len(profiles):
+		return ""
+	}
+
+	for {
+		fmt.Print("New profile name: ")
+		name := r.readLine()
+		if err := validate(name); err != nil {
+			fmt.Printf("Invalid name: %v\n", err)
+			continue
+		}
+		if slices.Contains(profiles, name) {
+			fmt.Printf("Profile %s already exists.\n", name)
+			continue
+		}
+		return name
+	}
+}
+
 // ChooseConfigStartMode asks how to start when a saved config exists
-func (r *Reader) ChooseConfigStartMode() ConfigStartMode {
+func (r *Reader) ChooseConfigStartMode(unit config.Unit) ConfigStartMode {
 	options := []string{
 		"Start fresh and enter all values",
 		"Reuse saved configuration as-is",
-		"Recalculate next cycle (+10 lbs squat/deadlift, +5 lbs bench/OHP)",
+		fmt.Sprintf("Recalculate next cycle (+%s squat/deadlift, +%s bench/OHP)",
+			unit.Format(config.CycleIncrement(config.Squat, unit)),
+			unit.Format(config.CycleIncrement(config.Bench, unit))),
 	}
 
 	choice := r.readChoice("How would you like to start?", options)
@@ -107,74 +205,447 @@ func (r *Reader) ChooseConfigStartMode() ConfigStartMode {
 func (r *Reader) GatherConfig() (*config.Config, error) {
 	cfg := config.NewDefaultConfig()
 
-	fmt.Print("\n=== 5/3/1 BBB Program Generator ===\n\n")
+	fmt.Print("\n=== 5/3/1 Program Generator ===\n\n")
 
-	// Step 1: Get 1RM values and determine if they're true 1RM or training max
-	fmt.Println("Enter your max for each lift.")
-	isTrueMax := r.readYesNo("Are these your TRUE 1RMs? (I'll calculate training max at 90%)")
+	// Step 0: Units
+	if r.readChoice("Which units do you train in?", []string{"Pounds (lbs)", "Kilograms (kg)"}) == 1 {
+		cfg.Units = config.Kilograms
+	}
 	fmt.Println()
 
-	for _, lift := range config.AllLifts() {
-		prompt := fmt.Sprintf("Enter %s max (lbs): ", lift)
-		maxVal := r.readFloat(prompt)
-		if isTrueMax {
-			cfg.TrainingMaxes[lift] = config.CalculateTrainingMax(maxVal)
-		} else {
-			cfg.TrainingMaxes[lift] = maxVal
+	// Custom main lifts
+	if r.readYesNo("Do you program main lifts beyond squat, bench, deadlift and OHP (e.g. front squat)?") {
+		cfg.CustomLifts = r.gatherCustomLifts()
+	}
+	fmt.Println()
+
+	// Step 1: Get 1RM values, training maxes or recent sets to estimate from
+	maxKinds := []string{
+		"True 1RMs (I'll calculate training max at 90%)",
+		"Training maxes",
+		"A recent weight x reps set, e.g. an AMRAP (I'll estimate the 1RM)",
+	}
+	maxKind := r.readChoice("What do you know for each lift?", maxKinds)
+	formula := e1rm.DefaultFormula
+	if maxKind == 2 {
+		names := make([]string, len(e1rm.Formulas))
+		for i, f := range e1rm.Formulas {
+			names[i] = string(f)
+		}
+		formula = e1rm.Formulas[r.readChoice("Estimate the 1RM with which formula?", names)]
+	}
+	fmt.Println()
+
+	for _, lift := range cfg.Lifts() {
+		switch maxKind {
+		case 0:
+			cfg.TrainingMaxes[lift] = config.CalculateTrainingMax(r.readFloat(fmt.Sprintf("Enter %s max (%s): ", lift, cfg.Units)))
+		case 1:
+			cfg.TrainingMaxes[lift] = r.readFloat(fmt.Sprintf("Enter %s training max (%s): ", lift, cfg.Units))
+		default:
+			cfg.TrainingMaxes[lift] = r.readEstimate(lift, cfg.Units, formula)
 		}
 	}
 
-	if isTrueMax {
-		fmt.Println("\nTraining maxes (90% of true 1RM):")
-		for _, lift := range config.AllLifts() {
-			fmt.Printf("  %s: %.0f lbs\n", lift, cfg.TrainingMaxes[lift])
+	if maxKind != 1 {
+		fmt.Println("\nTraining maxes (90% of 1RM):")
+		for _, lift := range cfg.Lifts() {
+			fmt.Printf("  %s: %s\n", lift, cfg.Units.Format(cfg.TrainingMaxes[lift]))
 		}
 	}
 
 	// Step 2: Customize lift order
 	fmt.Println("\n--- Lift Order ---")
 	fmt.Println("Default order: Day 1 = Squat, Day 2 = Bench, Day 3 = Deadlift, Day 4 = OHP")
-	if r.readYesNo("Would you like to customize the lift order?") {
-		cfg.LiftOrder = r.gatherLiftOrder()
+	if len(cfg.CustomLifts) > 0 || r.readYesNo("Would you like to customize the lift order?") {
+		cfg.LiftOrder = r.gatherLiftOrder(cfg.Lifts())
+		cfg.ApplyDefaults() // pair newly ordered lifts with themselves for BBB
+	}
+
+	frequencies := []string{
+		"4 days (one main lift per day)",
+		"3 days (one main lift per day, rolling over across weeks)",
+		"2 days (two main lifts per day)",
+	}
+	cfg.DaysPerWeek = 4 - r.readChoice("How many days per week do you train?", frequencies)
+
+	// Step 3: Template
+	templates := program.Templates()
+	if len(templates) > 1 {
+		fmt.Println("\n--- Program Template ---")
+		options := make([]string, len(templates))
+		for i, t := range templates {
+			options[i] = t.Title()
+		}
+		cfg.Template = templates[r.readChoice("Select a template:", options)].Name()
+	}
+
+	if cfg.Template == config.DefaultTemplate {
+		// Step 4: BBB percentage
+		fmt.Println("\n--- BBB Configuration ---")
+		progressions := []string{
+			"Same percentage every week",
+			"Different percentage each week (e.g., BBB Beefcake 50/60/70)",
+			"BBB 3-month challenge (60% -> 70% -> 80%, one step per cycle)",
+		}
+		switch r.readChoice("BBB progression:", progressions) {
+		case 1:
+			cfg.BBBWeekPercentages = make([]float64, len(config.BBBBeefcakeWeeks))
+			for i := range cfg.BBBWeekPercentages {
+				cfg.BBBWeekPercentages[i] = r.readFloat(fmt.Sprintf("Enter week %d BBB percentage (Beefcake: %.0f): ", i+1, config.BBBBeefcakeWeeks[i]))
+			}
+		case 2:
+			cfg.BBBChallenge = &config.BBBChallenge{
+				Percentages: append([]float64{}, config.BBBChallengeMonths...),
+				Month:       1,
+			}
+		default:
+			fmt.Printf("Default BBB percentage is 50%%.\n")
+			if r.readYesNo("Would you like to change the BBB percentage?") {
+				cfg.BBBPercentage = r.readFloat("Enter BBB percentage (e.g., 50 for 50%): ")
+			}
+		}
+
+		// Step 5: BBB pairing
+		fmt.Println("\nDefault BBB pairing: same lift (e.g., Squat day does BBB Squats)")
+		if r.readYesNo("Would you like to use opposite lift pairing?") {
+			cfg.BBBPairing = r.gatherBBBPairing(cfg.LiftOrder, cfg.Lifts())
+		}
+	} else {
+		// Step 4: FSL/SSL supplemental volume
+		fmt.Println("\n--- Supplemental Configuration ---")
+		fmt.Printf("Default supplemental volume is %dx%d.\n", cfg.Supplemental.Sets, cfg.Supplemental.Reps)
+		if r.readYesNo("Would you like to change the supplemental sets and reps?") {
+			cfg.Supplemental.Sets = r.readInt("Enter supplemental sets: ")
+			cfg.Supplemental.Reps = r.readInt("Enter supplemental reps: ")
+		}
+	}
+
+	// Step 6: Main work style
+	fmt.Println("\n--- Main Work ---")
+	styles := []string{"5/3/1 with AMRAP top sets", "5s PRO (every working set x5, no AMRAP)"}
+	if r.readChoice("Select main work style:", styles) == 1 {
+		cfg.MainWork = config.MainWork5sPro
+	} else if r.readYesNo("Add Joker sets after the top set on 3s and 1s weeks?") {
+		cfg.Jokers.Sets = r.readInt("How many Joker sets? ")
+		cfg.Jokers.Step = r.readPercentStep("Percent to add per Joker set (5-10): ")
+	}
+
+	// Cycle length and deloads
+	fmt.Println("\n--- Cycle Length ---")
+	fmt.Println("Default: 3 working weeks (5s, 3s, 5/3/1) followed by a deload week.")
+	if r.readYesNo("Would you like to change the cycle length or deload schedule?") {
+		cfg.CycleWeeks = r.readWeeks("Working weeks per cycle (1-3): ")
+		cfg.Deload = r.gatherDeloadPolicy()
 	}
 
-	// Step 3: BBB percentage
-	fmt.Println("\n--- BBB Configuration ---")
-	fmt.Printf("Default BBB percentage is 50%%.\n")
-	if r.readYesNo("Would you like to change the BBB percentage?") {
-		cfg.BBBPercentage = r.readFloat("Enter BBB percentage (e.g., 50 for 50%): ")
+	// Warm-ups
+	fmt.Println("\n--- Warm-ups ---")
+	fmt.Println("Default: 40% x5, 50% x5, 60% x3 before each lift's working sets (never lighter than the bar).")
+	if r.readYesNo("Would you like to customize the warm-ups?") {
+		cfg.Warmups = r.gatherWarmups(cfg.Lifts())
 	}
+	cfg.WarmupOnDeload = r.readYesNo("Warm up before deload week sets too?")
 
-	// Step 4: BBB pairing
-	fmt.Println("\nDefault BBB pairing: same lift (e.g., Squat day does BBB Squats)")
-	if r.readYesNo("Would you like to use opposite lift pairing?") {
-		cfg.BBBPairing = r.gatherBBBPairing(cfg.LiftOrder)
+	// Step 7: Rounding
+	fmt.Println("\n--- Rounding ---")
+	fmt.Printf("Default rounding: nearest %s.\n", cfg.Units.Format(cfg.Units.RoundingIncrement()))
+	if r.readYesNo("Would you like to change the rounding?") {
+		cfg.Rounding = r.gatherRounding(cfg.Units)
+	}
+	if r.readYesNo("Only use weights you can load with your gym's bar and plates?") {
+		cfg.Plates = r.gatherPlates(cfg.Units)
 	}
 
-	// Step 5: Accessories
+	// Step 8: Accessories
 	fmt.Println("\n--- Accessory Selection ---")
-	for _, lift := range cfg.LiftOrder {
-		options := config.AccessoryPresets[lift]
-		fmt.Printf("\n%s day accessory:\n", lift)
-		choice := r.readChoice("Select an accessory:", options)
-		cfg.Accessories[lift] = options[choice]
+	setups := []string{
+		"One accessory per day (5x10)",
+		"5/3/1 assistance: push, pull and single-leg/core work every day",
+	}
+	if r.readChoice("Accessory setup:", setups) == 1 {
+		cfg.Assistance = r.gatherAssistance(cfg.LiftOrder, cfg.Units)
+	} else {
+		for _, lift := range cfg.LiftOrder {
+			options := cfg.AccessoryOptions(lift)
+			if len(options) == 0 {
+				continue
+			}
+			fmt.Printf("\n%s day accessory:\n", lift)
+			choice := r.readChoice("Select an accessory:", options)
+			cfg.Accessories[lift] = options[choice]
+		}
+	}
+
+	// Step 9: Jumps, throws and conditioning
+	fmt.Println("\n--- Jumps, Throws and Conditioning ---")
+	if r.readYesNo("Add jumps/throws before the main lifts or conditioning after training?") {
+		cfg.Conditioning = r.gatherConditioning(cfg.LiftOrder)
+	}
+
+	// Step 10: Leader/anchor block
+	fmt.Println("\n--- Block Planning ---")
+	deload := cfg.HasDeload(false)
+	if deload {
+		fmt.Printf("Current plan: a single %d-week cycle ending with a deload week.\n", cfg.CycleWeeks+1)
+	} else {
+		fmt.Printf("Current plan: a single %d-week cycle with no deload week.\n", cfg.CycleWeeks)
+	}
+	if r.readYesNo("Plan a Forever-style leader/anchor block instead?") {
+		cfg.Block = r.gatherBlock(cfg.Template)
+	} else {
+		// Offer the cycle's own ending first, so a no-deload schedule isn't
+		// offered a regular deload week
+		ending := "No deload week"
+		if deload {
+			ending = "Regular deload week"
+		}
+		weeks := []string{ending, "7th-week deload", "7th-week training max test"}
+		switch r.readChoice("End the cycle with:", weeks) {
+		case 1:
+			cfg.SeventhWeek = config.SeventhWeekDeload
+		case 2:
+			cfg.SeventhWeek = config.SeventhWeekTMTest
+		}
+		if cfg.SeventhWeek != config.SeventhWeekNone && deload {
+			fmt.Println("Note: the 7th week takes the place of the cycle's deload week.")
+		}
 	}
 
 	return cfg, nil
 }
 
-// gatherLiftOrder lets the user specify custom lift order
-func (r *Reader) gatherLiftOrder() []config.Lift {
-	order := make([]config.Lift, 4)
+// gatherBlock asks for the leader cycles, 7th-week protocol and anchor of a
+// block. The leader uses the template already chosen.
+func (r *Reader) gatherBlock(leader string) *config.Block {
+	block := &config.Block{LeaderTemplate: leader}
+	block.LeaderCycles = r.readInt("How many leader cycles (usually 2-3)? ")
+
+	protocols := []string{"7th-week deload", "7th-week training max test", "No 7th week"}
+	switch r.readChoice("7th week protocol:", protocols) {
+	case 0:
+		block.SeventhWeek = config.SeventhWeekDeload
+	case 1:
+		block.SeventhWeek = config.SeventhWeekTMTest
+	}
+
+	block.AnchorCycles = r.readCount("How many anchor cycles (usually 1, 0 for none)? ")
+	if block.AnchorCycles > 0 {
+		templates := program.Templates()
+		options := make([]string, len(templates))
+		for i, t := range templates {
+			options[i] = t.Title()
+		}
+		block.AnchorTemplate = templates[r.readChoice("Select the anchor template:", options)].Name()
+	}
+	return block
+}
+
+// gatherDeloadPolicy asks when cycles should end with a deload week
+func (r *Reader) gatherDeloadPolicy() config.DeloadPolicy {
+	options := []string{
+		"Deload after every cycle",
+		"Never deload",
+		"Deload every few cycles",
+	}
+	switch r.readChoice("Deload schedule:", options) {
+	case 0:
+		return config.DeloadPolicy{Mode: config.DeloadAlways}
+	case 1:
+		return config.DeloadPolicy{Mode: config.DeloadNever}
+	default:
+		return config.DeloadPolicy{Mode: config.DeloadEvery, Every: r.readInt("Deload after every how many cycles? ")}
+	}
+}
+
+// gatherAssistance asks for one prescription per assistance category, then
+// an exercise from each category and its optional weight for every main
+// lift day
+func (r *Reader) gatherAssistance(liftOrder []config.Lift, unit config.Unit) map[config.Lift][]config.Assistance {
+	prescriptions := make(map[config.AssistanceCategory]config.Assistance)
+	for _, category := range config.AssistanceCategories {
+		fmt.Printf("\n%s work:\n", category)
+		a := config.Assistance{Category: category}
+		a.Sets = r.readInt("Sets: ")
+		if r.readChoice("Prescribe by:", []string{"Reps per set", "Total-rep target (e.g. 50-100)"}) == 1 {
+			a.TotalReps = &config.RepTarget{
+				Min: r.readInt("Minimum total reps: "),
+				Max: r.readInt("Maximum total reps: "),
+			}
+			if a.TotalReps.Max < a.TotalReps.Min {
+				a.TotalReps.Max = a.TotalReps.Min
+			}
+		} else {
+			a.Reps = r.readInt("Reps per set: ")
+		}
+		a.Rest = r.readCount("Rest between sets in seconds (0 to skip): ")
+		prescriptions[category] = a
+	}
+
+	assistance := make(map[config.Lift][]config.Assistance)
+	for _, lift := range liftOrder {
+		fmt.Printf("\n%s day assistance:\n", lift)
+		for _, category := range config.AssistanceCategories {
+			options := config.ActiveLibrary().InCategory(category)
+			a := prescriptions[category]
+			a.Exercise = options[r.readChoice(fmt.Sprintf("Select a %s exercise:", category), options)]
+			a.Weight = r.readWeight(fmt.Sprintf("%s weight in %s (0 for none): ", a.Exercise, unit))
+			assistance[lift] = append(assistance[lift], a)
+		}
+	}
+	return assistance
+}
+
+// gatherConditioning asks for jumps or throws to open each day with, then
+// for conditioning on the days the user picks
+func (r *Reader) gatherConditioning(liftOrder []config.Lift) map[config.Lift][]config.Conditioning {
+	conditioning := make(map[config.Lift][]config.Conditioning)
+	library := config.ActiveLibrary()
+
+	if r.readYesNo("Jumps or throws before the main lifts every day?") {
+		options := append(library.ForConditioning(config.ConditioningJumps), library.ForConditioning(config.ConditioningThrows)...)
+		exercise := options[r.readChoice("Select a jump or throw:", options)]
+		entry := config.Conditioning{
+			Exercise: exercise,
+			Kind:     config.ConditioningOf(exercise),
+			Sets:     r.readInt("Sets: "),
+			Reps:     r.readInt("Reps per set: "),
+		}
+		for _, lift := range liftOrder {
+			conditioning[lift] = append(conditioning[lift], entry)
+		}
+	}
+
+	if r.readYesNo("Conditioning after training?") {
+		options := library.ForConditioning(config.ConditioningCardio)
+		entry := config.Conditioning{
+			Exercise: options[r.readChoice("Select conditioning:", options)],
+			Kind:     config.ConditioningCardio,
+			Sets:     r.readInt("Sets (e.g. 10 hill sprints): "),
+		}
+		switch r.readChoice("Prescribe each set by:", []string{"Duration", "Distance", "Reps"}) {
+		case 0:
+			entry.Duration = r.readInt("Seconds per set: ")
+		case 1:
+			entry.Distance = r.readFloat("Meters per set: ")
+		default:
+			entry.Reps = r.readInt("Reps per set: ")
+		}
+		for _, lift := range liftOrder {
+			if r.readYesNo(fmt.Sprintf("Conditioning on %s day?", lift)) {
+				conditioning[lift] = append(conditioning[lift], entry)
+			}
+		}
+	}
+
+	return conditioning
+}
+
+// gatherWarmups asks for one warm-up for every lift, or one per lift
+func (r *Reader) gatherWarmups(lifts []config.Lift) map[config.Lift][]config.WarmupSet {
+	warmups := make(map[config.Lift][]config.WarmupSet)
+	if !r.readYesNo("Use a different warm-up for each lift?") {
+		sets := r.readWarmup("Warm-up for every lift")
+		for _, lift := range lifts {
+			warmups[lift] = sets
+		}
+		return warmups
+	}
+	for _, lift := range lifts {
+		warmups[lift] = r.readWarmup(fmt.Sprintf("%s warm-up", lift))
+	}
+	return warmups
+}
+
+// readWarmup reads a warm-up such as "bar x10, 30x5, 50x5, 70x3" or "none"
+func (r *Reader) readWarmup(label string) []config.WarmupSet {
+	for {
+		sets, err := config.ParseWarmup(r.ReadString(label + ", e.g. bar x10, 30x5, 50x5, 70x3 (or none): "))
+		if err == nil {
+			return sets
+		}
+		fmt.Printf("Invalid warm-up: %v\n", err)
+	}
+}
+
+// readEstimate reads a recent weight x reps set for a lift and returns the
+// training max estimated from it
+func (r *Reader) readEstimate(lift config.Lift, unit config.Unit, formula e1rm.Formula) float64 {
+	for {
+		result, err := e1rm.ParseResult(r.ReadString(fmt.Sprintf("Enter a recent %s set as WEIGHTxREPS in %s (e.g. 225x8): ", lift, unit)))
+		if err == nil {
+			var trainingMax float64
+			if trainingMax, err = config.EstimateTrainingMax(result, formula); err == nil {
+				return trainingMax
+			}
+		}
+		fmt.Printf("Invalid set: %v\n", err)
+	}
+}
+
+// gatherRounding lets the user choose a rounding increment and direction
+func (r *Reader) gatherRounding(unit config.Unit) config.Rounding {
+	rounding := config.Rounding{
+		Increment: r.readFloat(fmt.Sprintf("Enter rounding increment in %s (e.g., 5, 2.5, 1.25): ", unit)),
+	}
+
+	modes := []config.RoundingMode{config.RoundNearest, config.RoundDown, config.RoundUp}
+	options := []string{"Nearest", "Down (conservative)", "Up"}
+	rounding.Mode = modes[r.readChoice("Round weights:", options)]
+
+	return rounding
+}
+
+// gatherPlates lets the user describe their bar and available plates
+func (r *Reader) gatherPlates(unit config.Unit) *plates.Inventory {
+	inventory := plates.Standard(string(unit))
+	fmt.Printf("Default bar: %s\n", unit.Format(inventory.BarWeight))
+	if r.readYesNo("Is your bar a different weight?") {
+		inventory.BarWeight = r.readFloat(fmt.Sprintf("Enter bar weight (%s): ", unit))
+	}
+
+	for {
+		input := r.ReadString("Enter available plates per side, e.g. 45x4,25x2,10,5,2.5 (xN = pairs, blank for standard): ")
+		if input == "" {
+			break
+		}
+		parsed, err := plates.ParseList(input)
+		if err != nil {
+			fmt.Printf("Invalid plate list: %v\n", err)
+			continue
+		}
+		inventory.Plates = parsed
+		break
+	}
+
+	return &inventory
+}
+
+// gatherLiftOrder lets the user specify custom lift order, choosing one of
+// the given lifts for each day. With more than four lifts it first asks how
+// many of them to schedule.
+func (r *Reader) gatherLiftOrder(all []config.Lift) []config.Lift {
+	count := len(all)
+	if count > 4 {
+		for {
+			count = r.readInt(fmt.Sprintf("How many lifts would you like to schedule (1-%d)? ", len(all)))
+			if count <= len(all) {
+				break
+			}
+			fmt.Printf("There are only %d lifts to choose from.\n", len(all))
+		}
+	}
+
+	order := make([]config.Lift, count)
 	available := make(map[config.Lift]bool)
-	for _, lift := range config.AllLifts() {
+	for _, lift := range all {
 		available[lift] = true
 	}
 
-	for day := 1; day <= 4; day++ {
+	for day := 1; day <= count; day++ {
 		var options []string
 		var lifts []config.Lift
-		for _, lift := range config.AllLifts() {
+		for _, lift := range all {
 			if available[lift] {
 				options = append(options, string(lift))
 				lifts = append(lifts, lift)
@@ -189,29 +660,98 @@ func (r *Reader) gatherLiftOrder() []config.Lift {
 	return order
 }
 
-// gatherBBBPairing lets the user specify BBB lift pairings
-func (r *Reader) gatherBBBPairing(liftOrder []config.Lift) map[config.Lift]config.Lift {
+// gatherCustomLifts asks for main lifts beyond the standard four, with their
+// Hevy exercise name and accessory options
+func (r *Reader) gatherCustomLifts() []config.CustomLift {
+	var lifts []config.CustomLift
+	for {
+		fmt.Print("Lift name (blank when done): ")
+		name := r.readLine()
+		if name == "" {
+			return lifts
+		}
+		if lift, err := config.ParseLift(name); err == nil {
+			fmt.Printf("%s is already a main lift.\n", lift)
+			continue
+		}
+
+		custom := config.CustomLift{Name: config.Lift(name)}
+		custom.LowerBody = r.readYesNo("Is it a lower body lift (uses the bigger TM increase)?")
+
+		fmt.Print("Hevy exercise name (blank to match by lift name): ")
+		if hevyName := r.readLine(); hevyName != "" {
+			custom.HevyNames = []string{hevyName}
+		}
+
+		fmt.Print("Accessory options for its day, comma-separated (blank for none): ")
+		for _, accessory := range strings.Split(r.readLine(), ",") {
+			if accessory = strings.TrimSpace(accessory); accessory != "" {
+				custom.Accessories = append(custom.Accessories, accessory)
+			}
+		}
+
+		lifts = append(lifts, custom)
+	}
+}
+
+// gatherBBBPairing lets the user specify BBB lift pairings from the given lifts
+func (r *Reader) gatherBBBPairing(liftOrder, all []config.Lift) map[config.Lift]config.Lift {
 	pairing := make(map[config.Lift]config.Lift)
 
 	fmt.Println("\nFor each main lift, select the BBB lift:")
 	for _, mainLift := range liftOrder {
 		var options []string
-		for _, lift := range config.AllLifts() {
+		for _, lift := range all {
 			options = append(options, string(lift))
 		}
 		choice := r.readChoice(fmt.Sprintf("BBB lift for %s day:", mainLift), options)
-		pairing[mainLift] = config.AllLifts()[choice]
+		pairing[mainLift] = all[choice]
 	}
 
 	return pairing
 }
 
+// GatherTMTestReps asks for the reps made at 100% on each lift's 7th-week
+// TM test. It returns nil if the user has no results to enter.
+func (r *Reader) GatherTMTestReps(lifts []config.Lift) map[config.Lift]int {
+	fmt.Println("\n--- 7th-Week TM Test ---")
+	if !r.readYesNo("Enter your TM test results (reps made at 100%)?") {
+		return nil
+	}
+	reps := make(map[config.Lift]int, len(lifts))
+	for _, lift := range lifts {
+		reps[lift] = r.readCount(fmt.Sprintf("%s reps at 100%%: ", lift))
+	}
+	return reps
+}
+
+// GatherAMRAPReps asks for the reps made on each lift's 1+ AMRAP set last
+// cycle and whether lifts far behind should reset to 90% of their e1RM. It
+// returns nil reps if the user keeps the standard increases.
+func (r *Reader) GatherAMRAPReps(lifts []config.Lift) (map[config.Lift]int, bool) {
+	fmt.Println("\n--- AMRAP Results ---")
+	if !r.readYesNo("Base the new training maxes on last cycle's 1+ AMRAP sets?") {
+		return nil, false
+	}
+	reps := make(map[config.Lift]int, len(lifts))
+	for _, lift := range lifts {
+		reps[lift] = r.readCount(fmt.Sprintf("%s reps on the 1+ set: ", lift))
+	}
+	reset := r.readYesNo("Reset lifts whose e1RM fell far behind to 90% of it?")
+	return reps, reset
+}
+
+// ConfirmProgression asks whether to apply the training max decisions shown
+func (r *Reader) ConfirmProgression() bool {
+	return r.readYesNo("Apply these training maxes? (n keeps the standard increases)")
+}
+
 // GetOutputFilename prompts for the output filename
-func (r *Reader) GetOutputFilename() string {
-	fmt.Print("\nEnter output filename (default: 531_bbb.csv): ")
+func (r *Reader) GetOutputFilename(defaultName string) string {
+	fmt.Printf("\nEnter output filename (default: %s): ", defaultName)
 	filename := r.readLine()
 	if filename == "" {
-		return "531_bbb.csv"
+		return defaultName
 	}
 	if !strings.HasSuffix(filename, ".csv") {
 		filename += ".csv"